@@ -0,0 +1,95 @@
+package pocketframework
+
+import (
+  "github.com/pocketbase/pocketbase/core"
+  "github.com/pocketbase/pocketbase/tools/hook"
+)
+
+// AppHooks are the raw hooks of a pocketbase app which ScopedHooks builds upon. core.App implements it, see
+// ModuleAppHooks for the documentation of the individual hooks.
+type AppHooks interface {
+  OnBootstrap() *hook.Hook[*core.BootstrapEvent]
+  OnTerminate() *hook.Hook[*core.TerminateEvent]
+  OnBackupCreate() *hook.Hook[*core.BackupEvent]
+  OnBackupRestore() *hook.Hook[*core.BackupEvent]
+  OnModelValidate(tags ...string) *hook.TaggedHook[*core.ModelEvent]
+  OnModelCreate(tags ...string) *hook.TaggedHook[*core.ModelEvent]
+  OnModelCreateExecute(tags ...string) *hook.TaggedHook[*core.ModelEvent]
+  OnModelAfterCreateSuccess(tags ...string) *hook.TaggedHook[*core.ModelEvent]
+  OnModelAfterCreateError(tags ...string) *hook.TaggedHook[*core.ModelErrorEvent]
+  OnModelUpdate(tags ...string) *hook.TaggedHook[*core.ModelEvent]
+  OnModelUpdateExecute(tags ...string) *hook.TaggedHook[*core.ModelEvent]
+  OnModelAfterUpdateSuccess(tags ...string) *hook.TaggedHook[*core.ModelEvent]
+  OnModelAfterUpdateError(tags ...string) *hook.TaggedHook[*core.ModelErrorEvent]
+  OnModelDelete(tags ...string) *hook.TaggedHook[*core.ModelEvent]
+  OnModelDeleteExecute(tags ...string) *hook.TaggedHook[*core.ModelEvent]
+  OnModelAfterDeleteSuccess(tags ...string) *hook.TaggedHook[*core.ModelEvent]
+  OnModelAfterDeleteError(tags ...string) *hook.TaggedHook[*core.ModelErrorEvent]
+  OnRecordEnrich(tags ...string) *hook.TaggedHook[*core.RecordEnrichEvent]
+  OnRecordValidate(tags ...string) *hook.TaggedHook[*core.RecordEvent]
+  OnRecordCreate(tags ...string) *hook.TaggedHook[*core.RecordEvent]
+  OnRecordCreateExecute(tags ...string) *hook.TaggedHook[*core.RecordEvent]
+  OnRecordAfterCreateSuccess(tags ...string) *hook.TaggedHook[*core.RecordEvent]
+  OnRecordAfterCreateError(tags ...string) *hook.TaggedHook[*core.RecordErrorEvent]
+  OnRecordUpdate(tags ...string) *hook.TaggedHook[*core.RecordEvent]
+  OnRecordUpdateExecute(tags ...string) *hook.TaggedHook[*core.RecordEvent]
+  OnRecordAfterUpdateSuccess(tags ...string) *hook.TaggedHook[*core.RecordEvent]
+  OnRecordAfterUpdateError(tags ...string) *hook.TaggedHook[*core.RecordErrorEvent]
+  OnRecordDelete(tags ...string) *hook.TaggedHook[*core.RecordEvent]
+  OnRecordDeleteExecute(tags ...string) *hook.TaggedHook[*core.RecordEvent]
+  OnRecordAfterDeleteSuccess(tags ...string) *hook.TaggedHook[*core.RecordEvent]
+  OnRecordAfterDeleteError(tags ...string) *hook.TaggedHook[*core.RecordErrorEvent]
+  OnCollectionValidate(tags ...string) *hook.TaggedHook[*core.CollectionEvent]
+  OnCollectionCreate(tags ...string) *hook.TaggedHook[*core.CollectionEvent]
+  OnCollectionCreateExecute(tags ...string) *hook.TaggedHook[*core.CollectionEvent]
+  OnCollectionAfterCreateSuccess(tags ...string) *hook.TaggedHook[*core.CollectionEvent]
+  OnCollectionAfterCreateError(tags ...string) *hook.TaggedHook[*core.CollectionErrorEvent]
+  OnCollectionUpdate(tags ...string) *hook.TaggedHook[*core.CollectionEvent]
+  OnCollectionUpdateExecute(tags ...string) *hook.TaggedHook[*core.CollectionEvent]
+  OnCollectionAfterUpdateSuccess(tags ...string) *hook.TaggedHook[*core.CollectionEvent]
+  OnCollectionAfterUpdateError(tags ...string) *hook.TaggedHook[*core.CollectionErrorEvent]
+  OnCollectionDelete(tags ...string) *hook.TaggedHook[*core.CollectionEvent]
+  OnCollectionDeleteExecute(tags ...string) *hook.TaggedHook[*core.CollectionEvent]
+  OnCollectionAfterDeleteSuccess(tags ...string) *hook.TaggedHook[*core.CollectionEvent]
+  OnCollectionAfterDeleteError(tags ...string) *hook.TaggedHook[*core.CollectionErrorEvent]
+  OnMailerSend() *hook.Hook[*core.MailerEvent]
+  OnMailerRecordAuthAlertSend(tags ...string) *hook.TaggedHook[*core.MailerRecordEvent]
+  OnMailerRecordPasswordResetSend(tags ...string) *hook.TaggedHook[*core.MailerRecordEvent]
+  OnMailerRecordVerificationSend(tags ...string) *hook.TaggedHook[*core.MailerRecordEvent]
+  OnMailerRecordEmailChangeSend(tags ...string) *hook.TaggedHook[*core.MailerRecordEvent]
+  OnMailerRecordOTPSend(tags ...string) *hook.TaggedHook[*core.MailerRecordEvent]
+  OnRealtimeConnectRequest() *hook.Hook[*core.RealtimeConnectRequestEvent]
+  OnRealtimeMessageSend() *hook.Hook[*core.RealtimeMessageEvent]
+  OnRealtimeSubscribeRequest() *hook.Hook[*core.RealtimeSubscribeRequestEvent]
+  OnSettingsListRequest() *hook.Hook[*core.SettingsListRequestEvent]
+  OnSettingsUpdateRequest() *hook.Hook[*core.SettingsUpdateRequestEvent]
+  OnSettingsReload() *hook.Hook[*core.SettingsReloadEvent]
+  OnFileDownloadRequest(tags ...string) *hook.TaggedHook[*core.FileDownloadRequestEvent]
+  OnFileTokenRequest(tags ...string) *hook.TaggedHook[*core.FileTokenRequestEvent]
+  OnRecordAuthRequest(tags ...string) *hook.TaggedHook[*core.RecordAuthRequestEvent]
+  OnRecordAuthWithPasswordRequest(tags ...string) *hook.TaggedHook[*core.RecordAuthWithPasswordRequestEvent]
+  OnRecordAuthWithOAuth2Request(tags ...string) *hook.TaggedHook[*core.RecordAuthWithOAuth2RequestEvent]
+  OnRecordAuthRefreshRequest(tags ...string) *hook.TaggedHook[*core.RecordAuthRefreshRequestEvent]
+  OnRecordRequestPasswordResetRequest(tags ...string) *hook.TaggedHook[*core.RecordRequestPasswordResetRequestEvent]
+  OnRecordConfirmPasswordResetRequest(tags ...string) *hook.TaggedHook[*core.RecordConfirmPasswordResetRequestEvent]
+  OnRecordRequestVerificationRequest(tags ...string) *hook.TaggedHook[*core.RecordRequestVerificationRequestEvent]
+  OnRecordConfirmVerificationRequest(tags ...string) *hook.TaggedHook[*core.RecordConfirmVerificationRequestEvent]
+  OnRecordRequestEmailChangeRequest(tags ...string) *hook.TaggedHook[*core.RecordRequestEmailChangeRequestEvent]
+  OnRecordConfirmEmailChangeRequest(tags ...string) *hook.TaggedHook[*core.RecordConfirmEmailChangeRequestEvent]
+  OnRecordRequestOTPRequest(tags ...string) *hook.TaggedHook[*core.RecordCreateOTPRequestEvent]
+  OnRecordAuthWithOTPRequest(tags ...string) *hook.TaggedHook[*core.RecordAuthWithOTPRequestEvent]
+  OnRecordsListRequest(tags ...string) *hook.TaggedHook[*core.RecordsListRequestEvent]
+  OnRecordViewRequest(tags ...string) *hook.TaggedHook[*core.RecordRequestEvent]
+  OnRecordCreateRequest(tags ...string) *hook.TaggedHook[*core.RecordRequestEvent]
+  OnRecordUpdateRequest(tags ...string) *hook.TaggedHook[*core.RecordRequestEvent]
+  OnRecordDeleteRequest(tags ...string) *hook.TaggedHook[*core.RecordRequestEvent]
+  OnCollectionsListRequest() *hook.Hook[*core.CollectionsListRequestEvent]
+  OnCollectionViewRequest() *hook.Hook[*core.CollectionRequestEvent]
+  OnCollectionCreateRequest() *hook.Hook[*core.CollectionRequestEvent]
+  OnCollectionUpdateRequest() *hook.Hook[*core.CollectionRequestEvent]
+  OnCollectionDeleteRequest() *hook.Hook[*core.CollectionRequestEvent]
+  OnCollectionsImportRequest() *hook.Hook[*core.CollectionsImportRequestEvent]
+  OnBatchRequest() *hook.Hook[*core.BatchRequestEvent]
+}
+
+var _ AppHooks = core.App(nil)
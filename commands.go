@@ -0,0 +1,253 @@
+package pocketframework
+
+import (
+  "encoding/json"
+  "fmt"
+  "text/tabwriter"
+
+  "github.com/spf13/cobra"
+)
+
+// RegisterCommands adds the framework commands and the commands of every ModuleWithCommands to the given root
+// command, usually the RootCmd of the pocketbase app. It must be called after Init.
+func (m *ModuleRegistry) RegisterCommands(rootCmd *cobra.Command) error {
+  rootCmd.AddCommand(m.newRoutesCommand())
+  rootCmd.AddCommand(m.newOpenAPICommand())
+  rootCmd.AddCommand(m.newSchemaCommand())
+
+  for _, root := range m.roots {
+    command, err := m.newModuleCommand(root)
+    if err != nil {
+      return err
+    }
+    if command == nil {
+      continue
+    }
+
+    if existing := findCommand(rootCmd, command.Name()); existing != nil {
+      return fmt.Errorf("the commands of module %q conflict with the command %q", root.path, existing.CommandPath())
+    }
+    rootCmd.AddCommand(command)
+  }
+
+  return nil
+}
+
+func (m *ModuleRegistry) newSchemaCommand() *cobra.Command {
+  command := &cobra.Command{
+    Use:   "schema",
+    Short: "Compares and syncs the collections declared by the modules",
+  }
+
+  var asJSON bool
+  diff := &cobra.Command{
+    Use:          "diff",
+    Short:        "Prints the differences between the declared collections and the database",
+    SilenceUsage: true,
+    RunE: func(command *cobra.Command, args []string) error {
+      changes, err := m.SchemaDiff()
+      if err != nil {
+        return err
+      }
+
+      if asJSON {
+        encoder := json.NewEncoder(command.OutOrStdout())
+        encoder.SetIndent("", "  ")
+        return encoder.Encode(changes)
+      }
+
+      return printSchemaChanges(command, changes)
+    },
+  }
+  diff.Flags().BoolVar(&asJSON, "json", false, "print the changes as JSON")
+
+  var allowDestructive bool
+  sync := &cobra.Command{
+    Use:          "sync",
+    Short:        "Applies the declared collections to the database",
+    SilenceUsage: true,
+    RunE: func(command *cobra.Command, args []string) error {
+      result, err := m.SyncSchema(allowDestructive)
+      if err != nil {
+        return err
+      }
+
+      fmt.Fprintln(command.OutOrStdout(), "Applied:")
+      if err := printSchemaChanges(command, result.Applied); err != nil {
+        return err
+      }
+
+      if len(result.Skipped) > 0 {
+        fmt.Fprintln(command.OutOrStdout(), "\nSkipped:")
+        return printSchemaChanges(command, result.Skipped)
+      }
+      return nil
+    },
+  }
+  sync.Flags().BoolVar(&allowDestructive, "allow-destructive", false, "apply destructive changes, e.g. removing fields")
+
+  command.AddCommand(diff, sync)
+
+  return command
+}
+
+func printSchemaChanges(command *cobra.Command, changes []SchemaChange) error {
+  writer := tabwriter.NewWriter(command.OutOrStdout(), 0, 0, 2, ' ', 0)
+  fmt.Fprintln(writer, "MODULE\tCOLLECTION\tCHANGE\tTARGET\tDESTRUCTIVE")
+  for _, change := range changes {
+    fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%t\n", change.Module, change.Collection, change.Kind, change.Target, change.Destructive)
+  }
+  return writer.Flush()
+}
+
+// newModuleCommand returns the namespace command of a module containing its own commands and the namespaces of its
+// children, or nil if neither the module nor its children have commands.
+func (m *ModuleRegistry) newModuleCommand(node *moduleNode) (*cobra.Command, error) {
+  short := fmt.Sprintf("Commands of the module %q", node.path)
+  if moduleWithMetadata, ok := node.module.(ModuleWithMetadata); ok && moduleWithMetadata.Metadata().Description != "" {
+    short = moduleWithMetadata.Metadata().Description
+  }
+
+  namespace := &cobra.Command{
+    Use:          node.name,
+    Short:        short,
+    SilenceUsage: true,
+  }
+  // the commands of disabled modules are rejected like their routes
+  m.guardCommand(node, namespace)
+
+  if moduleWithCommands, ok := node.module.(ModuleWithCommands); ok {
+    for _, command := range moduleWithCommands.Commands(m.app) {
+      if existing := findCommand(namespace, command.Name()); existing != nil {
+        return nil, fmt.Errorf("module %q has more than one command %q", node.path, command.Name())
+      }
+      m.guardCommand(node, command)
+      namespace.AddCommand(command)
+    }
+  }
+
+  for _, child := range node.children {
+    command, err := m.newModuleCommand(child)
+    if err != nil {
+      return nil, err
+    }
+    if command == nil {
+      continue
+    }
+
+    if existing := findCommand(namespace, command.Name()); existing != nil {
+      return nil, fmt.Errorf("the commands of module %q conflict with the command %q", child.path, existing.CommandPath())
+    }
+    namespace.AddCommand(command)
+  }
+
+  if !namespace.HasSubCommands() {
+    return nil, nil
+  }
+
+  return namespace, nil
+}
+
+// guardCommand makes command and its sub commands fail if the module of node is disabled. Cobra only runs the
+// persistent pre-run of the nearest command defining one, so the pre-run of command and those of its sub commands are
+// wrapped with the check.
+func (m *ModuleRegistry) guardCommand(node *moduleNode, command *cobra.Command) {
+  preRunE, preRun := command.PersistentPreRunE, command.PersistentPreRun
+  command.PersistentPreRun = nil
+  command.PersistentPreRunE = func(command *cobra.Command, args []string) error {
+    if !m.nodeEnabled(node) {
+      return fmt.Errorf("module %q is disabled", node.path)
+    }
+
+    if preRunE != nil {
+      return preRunE(command, args)
+    }
+    if preRun != nil {
+      preRun(command, args)
+    }
+    return nil
+  }
+
+  for _, sub := range command.Commands() {
+    m.guardSubCommands(node, sub)
+  }
+}
+
+// guardSubCommands wraps the persistent pre-runs defined by command and its sub commands.
+func (m *ModuleRegistry) guardSubCommands(node *moduleNode, command *cobra.Command) {
+  if command.PersistentPreRunE != nil || command.PersistentPreRun != nil {
+    m.guardCommand(node, command)
+    return
+  }
+
+  for _, sub := range command.Commands() {
+    m.guardSubCommands(node, sub)
+  }
+}
+
+// findCommand returns the direct sub command of parent with the given name or alias, or nil if there is none.
+func findCommand(parent *cobra.Command, name string) *cobra.Command {
+  for _, command := range parent.Commands() {
+    if command.Name() == name || command.HasAlias(name) {
+      return command
+    }
+  }
+
+  return nil
+}
+
+func (m *ModuleRegistry) newRoutesCommand() *cobra.Command {
+  var asJSON bool
+
+  command := &cobra.Command{
+    Use:          "routes",
+    Short:        "Prints the routes registered by the modules",
+    SilenceUsage: true,
+    RunE: func(command *cobra.Command, args []string) error {
+      routes, err := m.Routes()
+      if err != nil {
+        return err
+      }
+
+      if asJSON {
+        encoder := json.NewEncoder(command.OutOrStdout())
+        encoder.SetIndent("", "  ")
+        return encoder.Encode(routes)
+      }
+
+      writer := tabwriter.NewWriter(command.OutOrStdout(), 0, 0, 2, ' ', 0)
+      fmt.Fprintln(writer, "METHOD\tPATH\tGROUP\tMODULE\tHANDLER")
+      for _, route := range routes {
+        fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", route.Method, route.Path, route.Group, route.Module, route.Handler)
+      }
+      return writer.Flush()
+    },
+  }
+
+  command.Flags().BoolVar(&asJSON, "json", false, "print the routes as JSON")
+
+  return command
+}
+
+func (m *ModuleRegistry) newOpenAPICommand() *cobra.Command {
+  return &cobra.Command{
+    Use:          "openapi [file]",
+    Short:        "Exports the OpenAPI document of the module routes",
+    Args:         cobra.MaximumNArgs(1),
+    SilenceUsage: true,
+    RunE: func(command *cobra.Command, args []string) error {
+      if len(args) == 1 {
+        return m.ExportOpenAPI(args[0])
+      }
+
+      doc, err := m.OpenAPI()
+      if err != nil {
+        return err
+      }
+
+      encoder := json.NewEncoder(command.OutOrStdout())
+      encoder.SetIndent("", "  ")
+      return encoder.Encode(doc)
+    },
+  }
+}
@@ -0,0 +1,237 @@
+package pocketframework
+
+import (
+  "bytes"
+  "encoding/json"
+  "strings"
+  "testing"
+
+  "github.com/pocketbase/pocketbase/core"
+  "github.com/spf13/cobra"
+)
+
+// commandsModule is a test module with CLI commands.
+type commandsModule struct {
+  testModule
+  commands func() []*cobra.Command
+}
+
+func (c *commandsModule) Commands(app core.App) []*cobra.Command {
+  return c.commands()
+}
+
+// executeCommand runs the CLI of the registry with the given args and returns its output.
+func executeCommand(t *testing.T, registry *ModuleRegistry, args ...string) (string, error) {
+  t.Helper()
+
+  root := &cobra.Command{Use: "app", SilenceErrors: true}
+  if err := registry.RegisterCommands(root); err != nil {
+    t.Fatalf("RegisterCommands() failed: %v", err)
+  }
+
+  var output bytes.Buffer
+  root.SetOut(&output)
+  root.SetErr(&output)
+  root.SetArgs(args)
+
+  err := root.Execute()
+  return output.String(), err
+}
+
+func TestModuleCommands(t *testing.T) {
+  calls := []string{}
+  record := func(name string) func(command *cobra.Command, args []string) {
+    return func(command *cobra.Command, args []string) {
+      calls = append(calls, name)
+    }
+  }
+
+  registry, _ := newTestRegistry(
+    t,
+    &commandsModule{
+      testModule: testModule{
+        name: "billing",
+        children: []Module{
+          &commandsModule{
+            testModule: testModule{name: "invoices"},
+            commands: func() []*cobra.Command {
+              return []*cobra.Command{{Use: "export", Run: record("export")}}
+            },
+          },
+        },
+      },
+      commands: func() []*cobra.Command {
+        recompute := &cobra.Command{Use: "recompute", PersistentPreRun: record("recompute pre-run"), Run: record("recompute")}
+        // the sub command defines its own pre-run, which hides the ones of its parents
+        recompute.AddCommand(
+          &cobra.Command{
+            Use: "all",
+            PersistentPreRunE: func(command *cobra.Command, args []string) error {
+              calls = append(calls, "all pre-run")
+              return nil
+            },
+            Run: record("all"),
+          },
+        )
+
+        return []*cobra.Command{recompute}
+      },
+    },
+    &testModule{name: "shop"},
+  )
+
+  if _, err := executeCommand(t, registry, "shop"); err == nil {
+    t.Errorf("expected no namespace for a module without commands")
+  }
+
+  tests := []struct {
+    args  []string
+    calls []string
+  }{
+    {args: []string{"billing", "recompute"}, calls: []string{"recompute pre-run", "recompute"}},
+    {args: []string{"billing", "recompute", "all"}, calls: []string{"all pre-run", "all"}},
+    {args: []string{"billing", "invoices", "export"}, calls: []string{"export"}},
+  }
+
+  for _, enabled := range []bool{true, false} {
+    if err := registry.SetEnabled("billing", enabled); err != nil {
+      t.Fatalf("SetEnabled() failed: %v", err)
+    }
+
+    for _, tt := range tests {
+      calls = []string{}
+
+      _, err := executeCommand(t, registry, tt.args...)
+      if enabled {
+        if err != nil {
+          t.Errorf("%v: unexpected error: %v", tt.args, err)
+        }
+        if strings.Join(calls, ",") != strings.Join(tt.calls, ",") {
+          t.Errorf("%v: expected the calls %v, got %v", tt.args, tt.calls, calls)
+        }
+        continue
+      }
+
+      module := "billing"
+      if tt.args[1] == "invoices" {
+        module = "billing/invoices"
+      }
+      if err == nil || err.Error() != `module "`+module+`" is disabled` {
+        t.Errorf("%v: expected the command of the disabled module to fail, got %v", tt.args, err)
+      }
+      if len(calls) != 0 {
+        t.Errorf("%v: expected nothing to run, got %v", tt.args, calls)
+      }
+    }
+  }
+}
+
+func TestModuleCommandConflicts(t *testing.T) {
+  command := func() []*cobra.Command {
+    return []*cobra.Command{{Use: "recompute", Run: func(command *cobra.Command, args []string) {}}}
+  }
+
+  tests := []struct {
+    name   string
+    module Module
+    err    string
+  }{
+    {
+      name: "duplicate command",
+      module: &commandsModule{
+        testModule: testModule{name: "billing"},
+        commands: func() []*cobra.Command {
+          return append(command(), command()...)
+        },
+      },
+      err: `module "billing" has more than one command "recompute"`,
+    },
+    {
+      name: "command of a child",
+      module: &commandsModule{
+        testModule: testModule{name: "billing", children: []Module{&commandsModule{testModule: testModule{name: "recompute"}, commands: command}}},
+        commands:   command,
+      },
+      err: `the commands of module "billing/recompute" conflict with the command "billing recompute"`,
+    },
+    {
+      name:   "framework command",
+      module: &commandsModule{testModule: testModule{name: "routes"}, commands: command},
+      err:    `the commands of module "routes" conflict with the command "app routes"`,
+    },
+  }
+
+  for _, tt := range tests {
+    t.Run(
+      tt.name, func(t *testing.T) {
+        registry := newHealthRegistry(t, nil, tt.module)
+
+        err := registry.RegisterCommands(&cobra.Command{Use: "app"})
+        if err == nil || err.Error() != tt.err {
+          t.Fatalf("expected the error %q, got %v", tt.err, err)
+        }
+      },
+    )
+  }
+}
+
+func listInvoices(e *core.RequestEvent) error {
+  return nil
+}
+
+func TestRoutesCommand(t *testing.T) {
+  registry := newHealthRegistry(
+    t, nil, &testModule{
+      name:   "billing",
+      prefix: "/billing",
+      routes: func(groups RouterGroups) error {
+        groups.Public.GET("/invoices", listInvoices)
+        return nil
+      },
+    },
+  )
+
+  output, err := executeCommand(t, registry, "routes")
+  if err != nil {
+    t.Fatal(err)
+  }
+  lines := strings.Split(strings.TrimSpace(output), "\n")
+  if strings.Join(strings.Fields(lines[0]), " ") != "METHOD PATH GROUP MODULE HANDLER" {
+    t.Errorf("expected the header first, got %q", lines[0])
+  }
+  if !containsRow(lines, "GET /api/billing/invoices public billing pocketframework.listInvoices") {
+    t.Errorf("expected the route of the module, got:\n%s", output)
+  }
+  // the framework routes have no module
+  if !containsRow(lines, "GET /api/healthz public pocketframework.(*ModuleRegistry).serveHealth-fm") {
+    t.Errorf("expected the framework routes, got:\n%s", output)
+  }
+
+  output, err = executeCommand(t, registry, "routes", "--json")
+  if err != nil {
+    t.Fatal(err)
+  }
+  routes := []RouteInfo{}
+  if err := json.Unmarshal([]byte(output), &routes); err != nil {
+    t.Fatalf("expected JSON, got %v:\n%s", err, output)
+  }
+  expected := RouteInfo{Method: "GET", Path: "/api/billing/invoices", Group: "public", Module: "billing", Handler: "pocketframework.listInvoices"}
+  found := false
+  for _, route := range routes {
+    found = found || route == expected
+  }
+  if !found {
+    t.Errorf("expected the route %+v, got %+v", expected, routes)
+  }
+}
+
+// containsRow reports whether one of the lines of a table has the given space separated cells.
+func containsRow(lines []string, row string) bool {
+  for _, line := range lines {
+    if strings.Join(strings.Fields(line), " ") == row {
+      return true
+    }
+  }
+
+  return false
+}
@@ -0,0 +1,197 @@
+package pocketframework
+
+import (
+  "context"
+  "fmt"
+  "reflect"
+  "slices"
+  "sync"
+  "time"
+
+  "github.com/pocketbase/pocketbase/core"
+)
+
+// Delivery defines when and where an event is delivered to a subscriber.
+type Delivery int
+
+const (
+  // DeliverSync calls the subscriber in the goroutine of the publisher. Events published inside
+  // EventBus.RunInTransaction are delivered after the transaction has been committed and dropped on rollback.
+  DeliverSync Delivery = iota
+
+  // DeliverAsync calls the subscriber in a new goroutine, after the transaction has been committed.
+  DeliverAsync
+
+  // DeliverInTransaction calls the subscriber in the goroutine of the publisher, inside the transaction if there is
+  // one. Use TxAppFromContext to access the transaction.
+  DeliverInTransaction
+)
+
+// EventBus delivers typed events between modules, so they do not have to import each other. Errors and panics of a
+// subscriber are logged and never reach the publisher or other subscribers.
+//
+// The registry provides it as a service, use Resolve[*EventBus] to access it.
+type EventBus struct {
+  app core.App
+
+  mu          sync.RWMutex
+  subscribers map[reflect.Type][]*subscription
+  nextId      int
+
+  wg sync.WaitGroup
+}
+
+type subscription struct {
+  id       int
+  name     string
+  delivery Delivery
+  fn       func(ctx context.Context, event any) error
+}
+
+type eventTxKey struct{}
+
+// eventTx collects the deliveries which have to wait for the commit of a transaction.
+type eventTx struct {
+  app core.App
+
+  mu      sync.Mutex
+  pending []func()
+}
+
+func newEventBus(app core.App) *EventBus {
+  return &EventBus{app: app, subscribers: map[reflect.Type][]*subscription{}}
+}
+
+// Events returns the event bus of the registry.
+func (m *ModuleRegistry) Events() *EventBus {
+  return m.events
+}
+
+// Subscribe registers fn for all events of type T and returns a function which removes the subscription again.
+func Subscribe[T any](bus *EventBus, delivery Delivery, fn func(ctx context.Context, event T) error) (unsubscribe func()) {
+  typ := reflect.TypeFor[T]()
+
+  bus.mu.Lock()
+  defer bus.mu.Unlock()
+
+  bus.nextId++
+  sub := &subscription{
+    id:       bus.nextId,
+    name:     funcName(fn),
+    delivery: delivery,
+    fn: func(ctx context.Context, event any) error {
+      return fn(ctx, event.(T))
+    },
+  }
+  bus.subscribers[typ] = append(bus.subscribers[typ], sub)
+
+  return func() {
+    bus.mu.Lock()
+    defer bus.mu.Unlock()
+
+    bus.subscribers[typ] = slices.DeleteFunc(
+      bus.subscribers[typ], func(existing *subscription) bool {
+        return existing.id == sub.id
+      },
+    )
+  }
+}
+
+// Publish delivers event to all subscribers of T according to their Delivery.
+func Publish[T any](ctx context.Context, bus *EventBus, event T) {
+  bus.mu.RLock()
+  subscribers := slices.Clone(bus.subscribers[reflect.TypeFor[T]()])
+  bus.mu.RUnlock()
+
+  tx, _ := ctx.Value(eventTxKey{}).(*eventTx)
+  for _, sub := range subscribers {
+    if sub.delivery == DeliverInTransaction || tx == nil {
+      bus.dispatch(ctx, sub, event)
+      continue
+    }
+
+    tx.mu.Lock()
+    tx.pending = append(tx.pending, func() { bus.dispatch(withoutEventTx(ctx), sub, event) })
+    tx.mu.Unlock()
+  }
+}
+
+// RunInTransaction runs fn in a database transaction. Events published with the ctx passed to fn are delivered to
+// DeliverInTransaction subscribers immediately and to all other subscribers once the transaction has been committed.
+// Nested calls join the outer transaction.
+func (b *EventBus) RunInTransaction(ctx context.Context, fn func(ctx context.Context, txApp core.App) error) error {
+  if tx, ok := ctx.Value(eventTxKey{}).(*eventTx); ok && tx != nil {
+    return fn(ctx, tx.app)
+  }
+
+  tx := &eventTx{}
+  err := b.app.RunInTransaction(
+    func(txApp core.App) error {
+      tx.app = txApp
+      return fn(context.WithValue(ctx, eventTxKey{}, tx), txApp)
+    },
+  )
+  if err != nil {
+    return err
+  }
+
+  for _, deliver := range tx.pending {
+    deliver()
+  }
+
+  return nil
+}
+
+// TxAppFromContext returns the transaction of EventBus.RunInTransaction if ctx belongs to one, otherwise app.
+func TxAppFromContext(ctx context.Context, app core.App) core.App {
+  if tx, ok := ctx.Value(eventTxKey{}).(*eventTx); ok && tx != nil {
+    return tx.app
+  }
+
+  return app
+}
+
+// wait waits for the running async deliveries up to the given timeout.
+func (b *EventBus) wait(timeout time.Duration) error {
+  done := make(chan struct{})
+  go func() {
+    b.wg.Wait()
+    close(done)
+  }()
+
+  select {
+  case <-done:
+    return nil
+  case <-time.After(timeout):
+    return fmt.Errorf("event deliveries did not finish within %s", timeout)
+  }
+}
+
+func (b *EventBus) dispatch(ctx context.Context, sub *subscription, event any) {
+  if sub.delivery != DeliverAsync {
+    b.deliver(ctx, sub, event)
+    return
+  }
+
+  b.wg.Add(1)
+  go func() {
+    defer b.wg.Done()
+    b.deliver(context.WithoutCancel(ctx), sub, event)
+  }()
+}
+
+func (b *EventBus) deliver(ctx context.Context, sub *subscription, event any) {
+  defer func() {
+    if r := recover(); r != nil {
+      b.app.Logger().Error("Event subscriber panicked", "event", fmt.Sprintf("%T", event), "subscriber", sub.name, "error", r)
+    }
+  }()
+
+  if err := sub.fn(ctx, event); err != nil {
+    b.app.Logger().Error("Event subscriber failed", "event", fmt.Sprintf("%T", event), "subscriber", sub.name, "error", err)
+  }
+}
+
+func withoutEventTx(ctx context.Context) context.Context {
+  return context.WithValue(ctx, eventTxKey{}, (*eventTx)(nil))
+}
@@ -0,0 +1,141 @@
+package pocketframework
+
+import (
+  "context"
+  "errors"
+  "sync/atomic"
+  "testing"
+  "time"
+
+  "github.com/pocketbase/pocketbase/core"
+)
+
+type invoicePaid struct {
+  Invoice string
+}
+
+func TestEventDelivery(t *testing.T) {
+  tests := []struct {
+    name     string
+    delivery Delivery
+
+    // inTransaction is whether the subscriber runs before the transaction has been committed
+    inTransaction bool
+    // afterRollback is whether the subscriber has run if the transaction is rolled back
+    afterRollback bool
+  }{
+    {name: "sync", delivery: DeliverSync},
+    {name: "async", delivery: DeliverAsync},
+    {name: "in transaction", delivery: DeliverInTransaction, inTransaction: true, afterRollback: true},
+  }
+
+  registry, _ := newTestRegistry(t)
+  bus := registry.Events()
+
+  for _, tt := range tests {
+    t.Run(
+      tt.name, func(t *testing.T) {
+        var delivered atomic.Int32
+        var sawTx atomic.Bool
+        unsubscribe := Subscribe(
+          bus, tt.delivery, func(ctx context.Context, event invoicePaid) error {
+            delivered.Add(1)
+            sawTx.Store(TxAppFromContext(ctx, nil) != nil)
+            return nil
+          },
+        )
+        defer unsubscribe()
+
+        count := func() int {
+          t.Helper()
+
+          if err := bus.wait(time.Second); err != nil {
+            t.Fatalf("wait() failed: %v", err)
+          }
+          return int(delivered.Swap(0))
+        }
+
+        Publish(context.Background(), bus, invoicePaid{Invoice: "1"})
+        if got := count(); got != 1 {
+          t.Errorf("deliveries outside of a transaction = %d, want 1", got)
+        }
+
+        err := bus.RunInTransaction(
+          context.Background(), func(ctx context.Context, txApp core.App) error {
+            Publish(ctx, bus, invoicePaid{Invoice: "2"})
+            if got := count(); got != boolCount(tt.inTransaction) {
+              t.Errorf("deliveries before the commit = %d, want %d", got, boolCount(tt.inTransaction))
+            }
+            return nil
+          },
+        )
+        if err != nil {
+          t.Fatalf("RunInTransaction() failed: %v", err)
+        }
+        if got := count(); got != boolCount(!tt.inTransaction) {
+          t.Errorf("deliveries after the commit = %d, want %d", got, boolCount(!tt.inTransaction))
+        }
+        if sawTx.Load() != tt.inTransaction {
+          t.Errorf("subscriber saw the transaction = %v, want %v", sawTx.Load(), tt.inTransaction)
+        }
+
+        rollback := errors.New("rollback")
+        err = bus.RunInTransaction(
+          context.Background(), func(ctx context.Context, txApp core.App) error {
+            Publish(ctx, bus, invoicePaid{Invoice: "3"})
+            return rollback
+          },
+        )
+        if !errors.Is(err, rollback) {
+          t.Fatalf("RunInTransaction() = %v, want %v", err, rollback)
+        }
+        if got := count(); got != boolCount(tt.afterRollback) {
+          t.Errorf("deliveries after the rollback = %d, want %d", got, boolCount(tt.afterRollback))
+        }
+      },
+    )
+  }
+}
+
+func TestEventSubscriberFailures(t *testing.T) {
+  registry, _ := newTestRegistry(t)
+  bus := registry.Events()
+
+  var delivered atomic.Int32
+  unsubscribePanic := Subscribe(
+    bus, DeliverSync, func(ctx context.Context, event invoicePaid) error {
+      panic("boom")
+    },
+  )
+  defer unsubscribePanic()
+  unsubscribeError := Subscribe(
+    bus, DeliverSync, func(ctx context.Context, event invoicePaid) error {
+      return errors.New("failed")
+    },
+  )
+  defer unsubscribeError()
+  unsubscribe := Subscribe(
+    bus, DeliverSync, func(ctx context.Context, event invoicePaid) error {
+      delivered.Add(1)
+      return nil
+    },
+  )
+
+  Publish(context.Background(), bus, invoicePaid{Invoice: "1"})
+  if got := delivered.Load(); got != 1 {
+    t.Errorf("deliveries after failing subscribers = %d, want 1", got)
+  }
+
+  unsubscribe()
+  Publish(context.Background(), bus, invoicePaid{Invoice: "2"})
+  if got := delivered.Load(); got != 1 {
+    t.Errorf("deliveries after unsubscribing = %d, want 1", got)
+  }
+}
+
+func boolCount(b bool) int {
+  if b {
+    return 1
+  }
+  return 0
+}
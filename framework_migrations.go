@@ -0,0 +1,88 @@
+package pocketframework
+
+import (
+  "github.com/pocketbase/pocketbase/core"
+)
+
+// frameworkMigrations returns the migrations of the framework itself. They are applied before the migrations of the
+// modules and are tracked under the name of the framework.
+func frameworkMigrations() []Migration {
+  return []Migration{
+    {
+      Name: "1_create_module_flags",
+      Up: func(txApp core.App) error {
+        collection := core.NewBaseCollection(FlagsCollection)
+        collection.System = true
+        collection.Fields.Add(
+          &core.TextField{Name: "module", Required: true, Presentable: true},
+          &core.BoolField{Name: "enabled"},
+          &core.AutodateField{Name: "updated", OnCreate: true, OnUpdate: true},
+        )
+        collection.AddIndex("idx_pocketframework_module_flags_module", true, "module", "")
+
+        return txApp.Save(collection)
+      },
+      Down: func(txApp core.App) error {
+        return deleteSystemCollection(txApp, FlagsCollection)
+      },
+    },
+    {
+      Name: "2_create_module_settings",
+      Up: func(txApp core.App) error {
+        collection := core.NewBaseCollection(SettingsCollection)
+        collection.System = true
+        collection.Fields.Add(
+          &core.TextField{Name: "module", Required: true, Presentable: true},
+          &core.JSONField{Name: "value"},
+          &core.AutodateField{Name: "updated", OnCreate: true, OnUpdate: true},
+        )
+        collection.AddIndex("idx_pocketframework_module_settings_module", true, "module", "")
+
+        return txApp.Save(collection)
+      },
+      Down: func(txApp core.App) error {
+        return deleteSystemCollection(txApp, SettingsCollection)
+      },
+    },
+    {
+      Name: "3_create_queue",
+      Up: func(txApp core.App) error {
+        collection := core.NewBaseCollection(QueueCollection)
+        collection.System = true
+        collection.Fields.Add(
+          &core.TextField{Name: "type", Required: true, Presentable: true},
+          &core.JSONField{Name: "payload"},
+          &core.SelectField{Name: "state", Required: true, MaxSelect: 1, Values: []string{string(QueueJobPending), string(QueueJobRunning), string(QueueJobDead)}},
+          &core.NumberField{Name: "attempts", OnlyInt: true},
+          &core.NumberField{Name: "max_attempts", OnlyInt: true},
+          &core.NumberField{Name: "run_at", OnlyInt: true},
+          &core.TextField{Name: "last_error"},
+          &core.AutodateField{Name: "created", OnCreate: true},
+          &core.AutodateField{Name: "updated", OnCreate: true, OnUpdate: true},
+        )
+        collection.AddIndex("idx_pocketframework_queue_due", false, "state, run_at", "")
+
+        return txApp.Save(collection)
+      },
+      Down: func(txApp core.App) error {
+        return deleteSystemCollection(txApp, QueueCollection)
+      },
+    },
+  }
+}
+
+// deleteSystemCollection deletes a collection created by a framework migration. System collections cannot be
+// deleted, so the flag is removed first.
+func deleteSystemCollection(txApp core.App, name string) error {
+  collection, err := txApp.FindCollectionByNameOrId(name)
+  if err != nil {
+    return err
+  }
+
+  collection.System = false
+  if err := txApp.SaveNoValidate(collection); err != nil {
+    return err
+  }
+
+  return txApp.Delete(collection)
+}
@@ -0,0 +1,137 @@
+package pocketframework
+
+// FrameworkRoutesPrefix is the prefix of the endpoints of the framework itself, below the API prefix.
+const FrameworkRoutesPrefix = "/_framework"
+
+// frameworkTag groups the framework endpoints in the OpenAPI document.
+const frameworkTag = "framework"
+
+// registerFrameworkRoutes mounts the endpoints of the framework itself next to the module routes.
+func (m *ModuleRegistry) registerFrameworkRoutes(groups RouterGroups) {
+  groups.Public.GET("/healthz", m.serveHealth).Doc(
+    RouteDoc{
+      OperationId: "health",
+      Summary:     "Runs the health checks of all modules",
+      Description: "Responds with 503 Service Unavailable if a check fails.",
+      Tags:        []string{frameworkTag},
+      Response:    HealthReport{},
+    },
+  )
+  groups.Public.GET("/readyz", m.serveReadiness).Doc(
+    RouteDoc{
+      OperationId: "readiness",
+      Summary:     "Reports whether all modules have been started and are healthy",
+      Description: "Responds with 503 Service Unavailable until all modules have been started or if a check fails.",
+      Tags:        []string{frameworkTag},
+      Response:    HealthReport{},
+    },
+  )
+
+  admin := groups.Admin.Group(FrameworkRoutesPrefix)
+
+  admin.GET("/metrics", m.serveMetrics).Doc(
+    RouteDoc{
+      OperationId: "metrics",
+      Summary:     "Exposes the request and hook metrics of all modules",
+      Description: "Responds in the Prometheus text exposition format.",
+      Tags:        []string{frameworkTag},
+    },
+  )
+
+  admin.GET("/modules", m.serveModules).Doc(
+    RouteDoc{
+      OperationId: "listModules",
+      Summary:     "Lists the registered modules",
+      Tags:        []string{frameworkTag},
+      Response:    []ModuleInfo{},
+    },
+  )
+
+  admin.GET("/flags", m.serveFlags).Doc(
+    RouteDoc{
+      OperationId: "listModuleFlags",
+      Summary:     "Lists the flags of all modules",
+      Tags:        []string{frameworkTag},
+      Response:    []ModuleFlag{},
+    },
+  )
+  admin.PUT("/flags/{module...}", Handle(m.serveSetFlag)).Doc(
+    RouteDoc{
+      OperationId: "setModuleFlag",
+      Summary:     "Enables or disables a module",
+      Tags:        []string{frameworkTag},
+      Request:     setFlagRequest{},
+      Response:    NoContent{},
+    },
+  )
+  admin.DELETE("/flags/{module...}", Handle(m.serveResetFlag)).Doc(
+    RouteDoc{
+      OperationId: "resetModuleFlag",
+      Summary:     "Resets the flag of a module to its env variable or the default",
+      Tags:        []string{frameworkTag},
+      Response:    NoContent{},
+    },
+  )
+
+  admin.GET("/settings", m.serveAllSettings).Doc(
+    RouteDoc{
+      OperationId: "listModuleSettings",
+      Summary:     "Lists the settings of all modules",
+      Tags:        []string{frameworkTag},
+      Response:    []SettingsInfo{},
+    },
+  )
+  admin.GET("/settings/{module...}", Handle(m.serveSettings)).Doc(
+    RouteDoc{
+      OperationId: "getModuleSettings",
+      Summary:     "Returns the settings of a module",
+      Tags:        []string{frameworkTag},
+      Response:    SettingsInfo{},
+    },
+  )
+  admin.PUT("/settings/{module...}", Handle(m.serveSetSettings)).Doc(
+    RouteDoc{
+      OperationId: "setModuleSettings",
+      Summary:     "Replaces the settings of a module",
+      Description: "Omitted fields are reset to their defaults. Invalid settings are rejected with 400 Bad Request.",
+      Tags:        []string{frameworkTag},
+      Request:     setSettingsRequest{},
+      Response:    SettingsInfo{},
+    },
+  )
+  admin.DELETE("/settings/{module...}", Handle(m.serveResetSettings)).Doc(
+    RouteDoc{
+      OperationId: "resetModuleSettings",
+      Summary:     "Resets the settings of a module to their defaults",
+      Tags:        []string{frameworkTag},
+      Response:    SettingsInfo{},
+    },
+  )
+
+  admin.GET("/schema", m.serveSchemaDiff).Doc(
+    RouteDoc{
+      OperationId: "schemaDiff",
+      Summary:     "Lists the differences between the collections declared by the modules and the database",
+      Description: "Destructive changes are only applied at bootstrap if enabled with WithDestructiveSchemaSync.",
+      Tags:        []string{frameworkTag},
+      Response:    []SchemaChange{},
+    },
+  )
+
+  admin.GET("/jobs", m.serveJobs).Doc(
+    RouteDoc{
+      OperationId: "listJobs",
+      Summary:     "Lists the jobs of all modules",
+      Tags:        []string{frameworkTag},
+      Response:    []JobInfo{},
+    },
+  )
+  admin.POST("/jobs/{id...}", Handle(m.serveTriggerJob)).Doc(
+    RouteDoc{
+      OperationId: "triggerJob",
+      Summary:     "Runs a job in the background",
+      Tags:        []string{frameworkTag},
+      Response:    NoContent{},
+    },
+  )
+}
@@ -0,0 +1,141 @@
+package pocketframework
+
+import (
+  "errors"
+  "fmt"
+  "net/http"
+  "reflect"
+  "strings"
+
+  validation "github.com/go-ozzo/ozzo-validation/v4"
+  "github.com/pocketbase/pocketbase/core"
+  "github.com/pocketbase/pocketbase/tools/router"
+)
+
+// NoContent can be used as response type of a typed handler which responds with 204 No Content.
+type NoContent struct{}
+
+// Handle adapts a typed function to a route action, so modules only have to write the business logic.
+//
+// The request is bound from the body first, then from the query string (fields with a "query" tag) and the path
+// (fields with a "path" tag). If the request implements validation.Validatable, it is validated before fn is
+// called. The response is sent as JSON with status 200.
+//
+// Errors returned by fn are sent as structured pocketbase api errors: *router.ApiError is sent as is,
+// validation.Errors as 400 Bad Request and every other error as 500 Internal Server Error.
+func Handle[Req any, Resp any](fn func(e *core.RequestEvent, req Req) (Resp, error)) func(e *core.RequestEvent) error {
+  return HandleWithStatus(http.StatusOK, fn)
+}
+
+// HandleWithStatus is like Handle but responds with the given status on success, e.g. http.StatusCreated.
+func HandleWithStatus[Req any, Resp any](status int, fn func(e *core.RequestEvent, req Req) (Resp, error)) func(e *core.RequestEvent) error {
+  return func(e *core.RequestEvent) error {
+    var req Req
+    if err := BindRequest(e, &req); err != nil {
+      return err
+    }
+
+    resp, err := fn(e, req)
+    if err != nil {
+      return toApiError(err)
+    }
+
+    if _, ok := any(resp).(NoContent); ok {
+      return e.NoContent(http.StatusNoContent)
+    }
+
+    return e.JSON(status, resp)
+  }
+}
+
+// BindRequest binds the body, query and path of the request into dst and validates it. It returns an api error
+// which can be returned from a route action as is.
+func BindRequest(e *core.RequestEvent, dst any) error {
+  if err := e.BindBody(dst); err != nil {
+    return e.BadRequestError("Failed to read the request body.", err)
+  }
+
+  value := reflect.ValueOf(dst)
+  if value.Kind() == reflect.Pointer && value.Elem().Kind() == reflect.Struct {
+    if err := bindRequestValues(e, value.Elem()); err != nil {
+      return err
+    }
+  }
+
+  if validatable, ok := dst.(validation.Validatable); ok {
+    if err := validatable.Validate(); err != nil {
+      return e.BadRequestError("Failed to validate the request.", err)
+    }
+  }
+
+  return nil
+}
+
+func bindRequestValues(e *core.RequestEvent, value reflect.Value) error {
+  query := e.Request.URL.Query()
+  fieldErrs := validation.Errors{}
+
+  for _, field := range reflect.VisibleFields(value.Type()) {
+    if !field.IsExported() {
+      continue
+    }
+
+    var raw string
+    var found bool
+
+    if name := field.Tag.Get("query"); name != "" {
+      if values, ok := query[name]; ok {
+        raw, found = strings.Join(values, ","), true
+      }
+    }
+
+    if name := field.Tag.Get("path"); name != "" {
+      if pathValue := e.Request.PathValue(name); pathValue != "" {
+        raw, found = pathValue, true
+      }
+    }
+
+    if !found {
+      continue
+    }
+
+    fieldValue, err := value.FieldByIndexErr(field.Index)
+    if err != nil {
+      continue
+    }
+
+    if err := setValueFromString(fieldValue, raw); err != nil {
+      fieldErrs[requestFieldName(field)] = validation.NewError("validation_invalid_value", fmt.Sprintf("Invalid value %q.", raw))
+    }
+  }
+
+  if len(fieldErrs) > 0 {
+    return e.BadRequestError("Failed to parse the request.", fieldErrs)
+  }
+
+  return nil
+}
+
+func requestFieldName(field reflect.StructField) string {
+  for _, tag := range []string{"path", "query", "json"} {
+    if name, _, _ := strings.Cut(field.Tag.Get(tag), ","); name != "" && name != "-" {
+      return name
+    }
+  }
+
+  return field.Name
+}
+
+func toApiError(err error) error {
+  var apiErr *router.ApiError
+  if errors.As(err, &apiErr) {
+    return apiErr
+  }
+
+  var validationErrs validation.Errors
+  if errors.As(err, &validationErrs) {
+    return router.NewBadRequestError("", validationErrs)
+  }
+
+  return router.NewInternalServerError("", err)
+}
@@ -0,0 +1,206 @@
+package pocketframework
+
+import (
+  "encoding/json"
+  "errors"
+  "net/http"
+  "net/http/httptest"
+  "strings"
+  "testing"
+
+  validation "github.com/go-ozzo/ozzo-validation/v4"
+  "github.com/pocketbase/pocketbase/core"
+  "github.com/pocketbase/pocketbase/tools/router"
+)
+
+type createInvoiceRequest struct {
+  Customer string `json:"customer"`
+  Amount   int    `json:"amount"`
+  Draft    bool   `json:"draft" query:"draft"`
+  Org      int    `json:"-" path:"org"`
+}
+
+func (r createInvoiceRequest) Validate() error {
+  return validation.ValidateStruct(&r, validation.Field(&r.Customer, validation.Required))
+}
+
+type createInvoiceResponse struct {
+  Customer string `json:"customer"`
+  Amount   int    `json:"amount"`
+  Draft    bool   `json:"draft"`
+  Org      int    `json:"org"`
+}
+
+// serveHandler serves a single request to an action mounted at "/orgs/{org}/invoices".
+func serveHandler(t *testing.T, action func(e *core.RequestEvent) error, target string, body string) *httptest.ResponseRecorder {
+  t.Helper()
+
+  r := router.NewRouter(
+    func(w http.ResponseWriter, r *http.Request) (*core.RequestEvent, router.EventCleanupFunc) {
+      event := new(core.RequestEvent)
+      event.Response = w
+      event.Request = r
+
+      return event, nil
+    },
+  )
+  r.POST("/orgs/{org}/invoices", action)
+
+  mux, err := r.BuildMux()
+  if err != nil {
+    t.Fatalf("failed to build the router: %v", err)
+  }
+
+  request := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+  request.Header.Set("Content-Type", "application/json")
+  recorder := httptest.NewRecorder()
+  mux.ServeHTTP(recorder, request)
+
+  return recorder
+}
+
+func TestHandle(t *testing.T) {
+  createInvoice := func(e *core.RequestEvent, req createInvoiceRequest) (createInvoiceResponse, error) {
+    if req.Customer == "broken" {
+      return createInvoiceResponse{}, errors.New("database is locked")
+    }
+    if req.Customer == "unknown" {
+      return createInvoiceResponse{}, router.NewNotFoundError("Unknown customer.", nil)
+    }
+    if req.Amount < 0 {
+      return createInvoiceResponse{}, validation.Errors{"amount": validation.NewError("validation_negative", "Must not be negative.")}
+    }
+
+    return createInvoiceResponse{Customer: req.Customer, Amount: req.Amount, Draft: req.Draft, Org: req.Org}, nil
+  }
+
+  tests := []struct {
+    name   string
+    action func(e *core.RequestEvent) error
+    target string
+    body   string
+    status int
+    expect string
+  }{
+    {
+      name:   "body, query and path",
+      action: Handle(createInvoice),
+      target: "/orgs/7/invoices?draft=true",
+      body:   `{"customer": "acme", "amount": 100}`,
+      status: http.StatusOK,
+      expect: `{"customer":"acme","amount":100,"draft":true,"org":7}`,
+    },
+    {
+      name:   "query overrides body",
+      action: Handle(createInvoice),
+      target: "/orgs/7/invoices?draft=false",
+      body:   `{"customer": "acme", "draft": true}`,
+      status: http.StatusOK,
+      expect: `{"customer":"acme","amount":0,"draft":false,"org":7}`,
+    },
+    {
+      name:   "custom status",
+      action: HandleWithStatus(http.StatusCreated, createInvoice),
+      target: "/orgs/7/invoices",
+      body:   `{"customer": "acme"}`,
+      status: http.StatusCreated,
+      expect: `{"customer":"acme","amount":0,"draft":false,"org":7}`,
+    },
+    {
+      name: "no content",
+      action: Handle(
+        func(e *core.RequestEvent, req createInvoiceRequest) (NoContent, error) {
+          return NoContent{}, nil
+        },
+      ),
+      target: "/orgs/7/invoices",
+      body:   `{"customer": "acme"}`,
+      status: http.StatusNoContent,
+    },
+    {
+      name:   "malformed body",
+      action: Handle(createInvoice),
+      target: "/orgs/7/invoices",
+      body:   `{"customer": `,
+      status: http.StatusBadRequest,
+      expect: "Failed to read the request body.",
+    },
+    {
+      name:   "invalid query value",
+      action: Handle(createInvoice),
+      target: "/orgs/7/invoices?draft=maybe",
+      body:   `{"customer": "acme"}`,
+      status: http.StatusBadRequest,
+      expect: `"draft":{"code":"validation_invalid_value","message":"Invalid value \"maybe\"."}`,
+    },
+    {
+      name:   "invalid path value",
+      action: Handle(createInvoice),
+      target: "/orgs/acme/invoices",
+      body:   `{"customer": "acme"}`,
+      status: http.StatusBadRequest,
+      expect: `"org":{"code":"validation_invalid_value"`,
+    },
+    {
+      name:   "failed validation",
+      action: Handle(createInvoice),
+      target: "/orgs/7/invoices",
+      body:   `{"amount": 100}`,
+      status: http.StatusBadRequest,
+      expect: `"customer":{"code":"validation_required"`,
+    },
+    {
+      name:   "api error",
+      action: Handle(createInvoice),
+      target: "/orgs/7/invoices",
+      body:   `{"customer": "unknown"}`,
+      status: http.StatusNotFound,
+      expect: "Unknown customer.",
+    },
+    {
+      name:   "validation errors",
+      action: Handle(createInvoice),
+      target: "/orgs/7/invoices",
+      body:   `{"customer": "acme", "amount": -1}`,
+      status: http.StatusBadRequest,
+      expect: `"amount":{"code":"validation_negative","message":"Must not be negative."}`,
+    },
+    {
+      name:   "other errors",
+      action: Handle(createInvoice),
+      target: "/orgs/7/invoices",
+      body:   `{"customer": "broken"}`,
+      status: http.StatusInternalServerError,
+    },
+  }
+
+  for _, test := range tests {
+    t.Run(
+      test.name, func(t *testing.T) {
+        recorder := serveHandler(t, test.action, test.target, test.body)
+
+        if recorder.Code != test.status {
+          t.Fatalf("expected status %d, got %d: %s", test.status, recorder.Code, recorder.Body)
+        }
+
+        body := strings.TrimSpace(recorder.Body.String())
+        if test.status == http.StatusNoContent && body != "" {
+          t.Fatalf("expected an empty body, got %q", body)
+        }
+        if !strings.Contains(body, test.expect) {
+          t.Fatalf("expected the body to contain %q, got %q", test.expect, body)
+        }
+        if strings.Contains(body, "database is locked") {
+          t.Fatalf("expected the internal error not to be exposed, got %q", body)
+        }
+
+        if test.status >= http.StatusBadRequest {
+          var apiErr router.ApiError
+          if err := json.Unmarshal(recorder.Body.Bytes(), &apiErr); err != nil || apiErr.Status != test.status {
+            t.Fatalf("expected a pocketbase api error with status %d, got %q", test.status, body)
+          }
+        }
+      },
+    )
+  }
+}
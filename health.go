@@ -0,0 +1,166 @@
+package pocketframework
+
+import (
+  "context"
+  "fmt"
+  "net/http"
+  "sync"
+  "time"
+
+  "github.com/pocketbase/pocketbase/core"
+)
+
+// DefaultHealthCheckTimeout is the time a single health check may take unless configured otherwise.
+const DefaultHealthCheckTimeout = 5 * time.Second
+
+// HealthStatus is the outcome of a health check.
+type HealthStatus string
+
+const (
+  HealthStatusOK      HealthStatus = "ok"
+  HealthStatusFailing HealthStatus = "failing"
+
+  // HealthStatusDisabled is reported for disabled modules, which are not checked and do not affect the overall
+  // status.
+  HealthStatusDisabled HealthStatus = "disabled"
+)
+
+// HealthReport is the response of the health and readiness endpoints.
+type HealthReport struct {
+  Status  HealthStatus   `json:"status"`
+  Modules []ModuleHealth `json:"modules"`
+}
+
+// ModuleHealth is the health of a single module.
+type ModuleHealth struct {
+  Module   string        `json:"module"`
+  State    ModuleState   `json:"state"`
+  Status   HealthStatus  `json:"status"`
+  Duration time.Duration `json:"duration"`
+
+  // Error is the reason the module is failing. The health endpoints omit it and log it instead, as they are public.
+  Error string `json:"error,omitempty"`
+}
+
+// WithHealthCheckTimeout sets how long a single health check may take. Defaults to DefaultHealthCheckTimeout.
+func WithHealthCheckTimeout(timeout time.Duration) RegistryOption {
+  return func(m *ModuleRegistry) {
+    m.healthTimeout = timeout
+  }
+}
+
+// Health runs the health checks of all enabled modules in parallel.
+func (m *ModuleRegistry) Health(ctx context.Context) HealthReport {
+  return m.checkHealth(ctx, false)
+}
+
+// Readiness is like Health but additionally reports modules which have not been started as failing, so the app is
+// only ready once all modules finished their startup.
+func (m *ModuleRegistry) Readiness(ctx context.Context) HealthReport {
+  return m.checkHealth(ctx, true)
+}
+
+func (m *ModuleRegistry) checkHealth(ctx context.Context, readiness bool) HealthReport {
+  report := HealthReport{Status: HealthStatusOK, Modules: make([]ModuleHealth, len(m.ordered))}
+
+  // the states are read at once, so the report does not look up every module
+  m.mu.RLock()
+  for i, node := range m.ordered {
+    report.Modules[i] = ModuleHealth{Module: node.path, State: node.state, Status: HealthStatusOK}
+  }
+  m.mu.RUnlock()
+
+  var wg sync.WaitGroup
+  for i, node := range m.ordered {
+    state := report.Modules[i].State
+
+    switch {
+    case !m.nodeEnabled(node):
+      report.Modules[i].Status = HealthStatusDisabled
+      continue
+    case readiness && state != ModuleStateStarted:
+      report.Modules[i].Status = HealthStatusFailing
+      report.Modules[i].Error = fmt.Sprintf("module is %s", state)
+      continue
+    }
+
+    moduleWithHealthCheck, ok := node.module.(ModuleWithHealthCheck)
+    if !ok {
+      continue
+    }
+
+    wg.Add(1)
+    go func(health *ModuleHealth) {
+      defer wg.Done()
+
+      started := time.Now()
+      if err := m.runHealthCheck(ctx, moduleWithHealthCheck); err != nil {
+        health.Status = HealthStatusFailing
+        health.Error = err.Error()
+      }
+      health.Duration = time.Since(started)
+    }(&report.Modules[i])
+  }
+  wg.Wait()
+
+  for _, health := range report.Modules {
+    if health.Status == HealthStatusFailing {
+      report.Status = HealthStatusFailing
+    }
+  }
+
+  return report
+}
+
+// runHealthCheck runs a single check and gives up once the timeout expires, even if the check ignores its ctx.
+func (m *ModuleRegistry) runHealthCheck(ctx context.Context, module ModuleWithHealthCheck) error {
+  ctx, cancel := context.WithTimeout(ctx, m.healthTimeout)
+  defer cancel()
+
+  done := make(chan error, 1)
+  go func() {
+    defer func() {
+      if r := recover(); r != nil {
+        done <- fmt.Errorf("health check panicked: %v", r)
+      }
+    }()
+    done <- module.HealthCheck(ctx)
+  }()
+
+  select {
+  case err := <-done:
+    return err
+  case <-ctx.Done():
+    return fmt.Errorf("health check did not finish within %s", m.healthTimeout)
+  }
+}
+
+func (m *ModuleRegistry) serveHealth(e *core.RequestEvent) error {
+  return serveHealthReport(e, m.Health(e.Request.Context()))
+}
+
+func (m *ModuleRegistry) serveReadiness(e *core.RequestEvent) error {
+  return serveHealthReport(e, m.Readiness(e.Request.Context()))
+}
+
+// serveHealthReport responds with the report without the errors of the checks, as the endpoints are public. The
+// errors are logged instead.
+func serveHealthReport(e *core.RequestEvent, report HealthReport) error {
+  status := http.StatusOK
+  if report.Status != HealthStatusOK {
+    status = http.StatusServiceUnavailable
+  }
+
+  modules := make([]ModuleHealth, len(report.Modules))
+  for i, health := range report.Modules {
+    if health.Error != "" {
+      e.App.Logger().Warn("Module is not healthy", "module", health.Module, "state", health.State, "error", health.Error)
+    }
+
+    modules[i] = health
+    modules[i].Error = ""
+  }
+  report.Modules = modules
+
+  return e.JSON(status, report)
+}
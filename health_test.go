@@ -0,0 +1,162 @@
+package pocketframework
+
+import (
+  "context"
+  "errors"
+  "net/http"
+  "net/http/httptest"
+  "strings"
+  "sync"
+  "testing"
+  "time"
+)
+
+// healthModule is a test module with a configurable health check.
+type healthModule struct {
+  testModule
+  check func(ctx context.Context) error
+}
+
+func (h *healthModule) HealthCheck(ctx context.Context) error {
+  return h.check(ctx)
+}
+
+// newHealthRegistry returns a registry with the given modules which has not been bootstrapped.
+func newHealthRegistry(t *testing.T, opts []RegistryOption, modules ...Module) *ModuleRegistry {
+  t.Helper()
+
+  m := NewModuleRegistry(nil, "/api", opts...)
+  for _, module := range modules {
+    m.Register(module)
+  }
+
+  var err error
+  if m.roots, m.ordered, err = buildModuleTree(m.modules); err != nil {
+    t.Fatal(err)
+  }
+
+  return m
+}
+
+func TestHealthChecksRunInParallel(t *testing.T) {
+  const checks = 3
+
+  // every check waits until all checks are running, which never happens if they run one after another
+  var wg sync.WaitGroup
+  wg.Add(checks)
+  check := func(ctx context.Context) error {
+    wg.Done()
+    wg.Wait()
+    return nil
+  }
+
+  modules := []Module{}
+  for _, name := range []string{"billing", "crm", "users"} {
+    modules = append(modules, &healthModule{testModule: testModule{name: name}, check: check})
+  }
+  m := newHealthRegistry(t, []RegistryOption{WithHealthCheckTimeout(time.Second)}, modules...)
+
+  report := m.Health(context.Background())
+  if report.Status != HealthStatusOK {
+    t.Fatalf("expected all checks to pass, got %+v", report)
+  }
+}
+
+func TestHealthCheckTimeout(t *testing.T) {
+  release := make(chan struct{})
+  defer close(release)
+
+  m := newHealthRegistry(
+    t, []RegistryOption{WithHealthCheckTimeout(50 * time.Millisecond)},
+    &healthModule{
+      testModule: testModule{name: "billing"},
+      check: func(ctx context.Context) error {
+        // ignores ctx on purpose
+        <-release
+        return nil
+      },
+    },
+    &healthModule{
+      testModule: testModule{name: "crm"},
+      check: func(ctx context.Context) error {
+        return nil
+      },
+    },
+  )
+
+  started := time.Now()
+  report := m.Health(context.Background())
+  if elapsed := time.Since(started); elapsed > time.Second {
+    t.Fatalf("expected the report to be done after the timeout, took %s", elapsed)
+  }
+
+  if report.Status != HealthStatusFailing {
+    t.Fatalf("expected the report to be failing, got %+v", report)
+  }
+  billing := report.Modules[0]
+  if billing.Status != HealthStatusFailing || billing.Error != "health check did not finish within 50ms" {
+    t.Errorf("expected billing to time out, got %+v", billing)
+  }
+  if crm := report.Modules[1]; crm.Status != HealthStatusOK {
+    t.Errorf("expected crm to be healthy, got %+v", crm)
+  }
+}
+
+func TestReadinessBeforeStartup(t *testing.T) {
+  m := newHealthRegistry(t, nil, &testModule{name: "billing"})
+
+  if report := m.Health(context.Background()); report.Status != HealthStatusOK {
+    t.Errorf("expected the app to be healthy, got %+v", report)
+  }
+
+  report := m.Readiness(context.Background())
+  expected := ModuleHealth{Module: "billing", State: ModuleStateRegistered, Status: HealthStatusFailing, Error: "module is registered"}
+  if report.Status != HealthStatusFailing || len(report.Modules) != 1 || report.Modules[0] != expected {
+    t.Fatalf("expected the app not to be ready, got %+v", report)
+  }
+
+  if err := m.startModules(); err != nil {
+    t.Fatal(err)
+  }
+  if report := m.Readiness(context.Background()); report.Status != HealthStatusOK {
+    t.Errorf("expected the app to be ready once started, got %+v", report)
+  }
+}
+
+func TestHealthEndpoints(t *testing.T) {
+  registry, app := newTestRegistry(
+    t, &healthModule{
+      testModule: testModule{name: "billing"},
+      check: func(ctx context.Context) error {
+        return errors.New("dial tcp 10.0.0.7:6379: connection refused")
+      },
+    },
+  )
+  handler := serveTestRegistry(t, registry, app)
+
+  for _, path := range []string{"/api/healthz", "/api/readyz"} {
+    t.Run(
+      path, func(t *testing.T) {
+        recorder := httptest.NewRecorder()
+        handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+
+        if recorder.Code != http.StatusServiceUnavailable {
+          t.Fatalf("expected status %d, got %d: %s", http.StatusServiceUnavailable, recorder.Code, recorder.Body)
+        }
+
+        body := recorder.Body.String()
+        if !strings.Contains(body, `"status":"failing"`) {
+          t.Errorf("expected the status in the body, got %s", body)
+        }
+        if strings.Contains(body, "connection refused") || strings.Contains(body, `"error"`) {
+          t.Errorf("expected the error of the check not to be exposed, got %s", body)
+        }
+      },
+    )
+  }
+
+  // the report returned by Health still holds the error
+  if report := registry.Health(context.Background()); report.Modules[0].Error != "dial tcp 10.0.0.7:6379: connection refused" {
+    t.Errorf("expected the error in the report, got %+v", report)
+  }
+}
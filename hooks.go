@@ -0,0 +1,196 @@
+package pocketframework
+
+import (
+  "slices"
+  "sync"
+
+  "github.com/pocketbase/pocketbase/tools/hook"
+)
+
+// HookBinding describes a handler which a module bound through its ModuleAppHooks.
+type HookBinding struct {
+  Hook string   `json:"hook"`
+  Tags []string `json:"tags,omitempty"`
+  Id   string   `json:"id"`
+}
+
+// hookScope records the handlers bound by a single module.
+type hookScope struct {
+  mu       sync.RWMutex
+  bindings []scopedBinding
+
+  // active reports whether the handlers of the module should run. Nil means always.
+  active func() bool
+
+  // instrument is called before every invocation of a handler of the module, if set. The returned function is
+  // called with the result of the handler.
+  instrument func(hookName string, event any) (done func(err error))
+}
+
+type scopedBinding struct {
+  HookBinding
+
+  unbind func()
+}
+
+func (s *hookScope) add(binding HookBinding, unbind func()) {
+  s.mu.Lock()
+  defer s.mu.Unlock()
+
+  // binding a handler with an existing id replaces the old handler
+  for i, existing := range s.bindings {
+    if existing.Hook == binding.Hook && existing.Id == binding.Id {
+      s.bindings[i] = scopedBinding{HookBinding: binding, unbind: unbind}
+      return
+    }
+  }
+
+  s.bindings = append(s.bindings, scopedBinding{HookBinding: binding, unbind: unbind})
+}
+
+func (s *hookScope) remove(hookName string, ids ...string) {
+  s.mu.Lock()
+  defer s.mu.Unlock()
+
+  s.bindings = slices.DeleteFunc(
+    s.bindings, func(binding scopedBinding) bool {
+      return binding.Hook == hookName && slices.Contains(ids, binding.Id)
+    },
+  )
+}
+
+// wrapHandler returns a copy of the handler which is skipped while the scope is inactive and reports its
+// invocations to the scope.
+func wrapHandler[T hook.Resolver](scope *hookScope, hookName string, handler *hook.Handler[T]) *hook.Handler[T] {
+  wrapped := *handler
+  wrapped.Func = func(e T) error {
+    if scope.active != nil && !scope.active() {
+      return e.Next()
+    }
+    if scope.instrument == nil {
+      return handler.Func(e)
+    }
+
+    done := scope.instrument(hookName, e)
+    err := handler.Func(e)
+    done(err)
+
+    return err
+  }
+
+  return &wrapped
+}
+
+func (s *hookScope) list() []HookBinding {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+
+  bindings := make([]HookBinding, 0, len(s.bindings))
+  for _, binding := range s.bindings {
+    bindings = append(bindings, binding.HookBinding)
+  }
+
+  return bindings
+}
+
+// unbindAll removes every recorded handler from its hook.
+func (s *hookScope) unbindAll() {
+  s.mu.Lock()
+  bindings := s.bindings
+  s.bindings = nil
+  s.mu.Unlock()
+
+  for _, binding := range bindings {
+    binding.unbind()
+  }
+}
+
+// Hook is a pocketbase hook which records the handlers a module binds to it.
+type Hook[T hook.Resolver] struct {
+  hook  *hook.Hook[T]
+  name  string
+  scope *hookScope
+}
+
+func newHook[T hook.Resolver](scope *hookScope, name string, h *hook.Hook[T]) *Hook[T] {
+  return &Hook[T]{hook: h, name: name, scope: scope}
+}
+
+// Bind registers the provided handler to the hook and returns its id.
+func (h *Hook[T]) Bind(handler *hook.Handler[T]) string {
+  id := h.hook.Bind(wrapHandler(h.scope, h.name, handler))
+  handler.Id = id
+  h.scope.add(HookBinding{Hook: h.name, Id: id}, func() { h.hook.Unbind(id) })
+
+  return id
+}
+
+// BindFunc is like Bind but registers a new handler from the provided function.
+func (h *Hook[T]) BindFunc(fn func(e T) error) string {
+  return h.Bind(&hook.Handler[T]{Func: fn})
+}
+
+// Unbind removes the handlers with the given ids from the hook.
+func (h *Hook[T]) Unbind(idsToRemove ...string) {
+  h.hook.Unbind(idsToRemove...)
+  h.scope.remove(h.name, idsToRemove...)
+}
+
+// Length returns the number of handlers bound to the hook by all modules and pocketbase itself.
+func (h *Hook[T]) Length() int {
+  return h.hook.Length()
+}
+
+// Trigger executes all handlers of the hook, see hook.Hook.Trigger.
+func (h *Hook[T]) Trigger(event T, oneOffHandlerFuncs ...func(T) error) error {
+  return h.hook.Trigger(event, oneOffHandlerFuncs...)
+}
+
+// Unwrap returns the pocketbase hook, e.g. to pass it to a function which expects one. Handlers bound to it directly
+// are not recorded.
+func (h *Hook[T]) Unwrap() *hook.Hook[T] {
+  return h.hook
+}
+
+// TaggedHook is a pocketbase tagged hook which records the handlers a module binds to it.
+type TaggedHook[T hook.Tagger] struct {
+  hook  *hook.TaggedHook[T]
+  name  string
+  tags  []string
+  scope *hookScope
+}
+
+func newTaggedHook[T hook.Tagger](scope *hookScope, name string, h *hook.TaggedHook[T], tags []string) *TaggedHook[T] {
+  return &TaggedHook[T]{hook: h, name: name, tags: tags, scope: scope}
+}
+
+// CanTriggerOn checks if the hook handlers can be triggered for an event with the given tags.
+func (h *TaggedHook[T]) CanTriggerOn(tagsToCheck []string) bool {
+  return h.hook.CanTriggerOn(tagsToCheck)
+}
+
+// Bind registers the provided handler to the hook and returns its id.
+func (h *TaggedHook[T]) Bind(handler *hook.Handler[T]) string {
+  id := h.hook.Bind(wrapHandler(h.scope, h.name, handler))
+  handler.Id = id
+  h.scope.add(HookBinding{Hook: h.name, Tags: h.tags, Id: id}, func() { h.hook.Unbind(id) })
+
+  return id
+}
+
+// BindFunc is like Bind but registers a new handler from the provided function.
+func (h *TaggedHook[T]) BindFunc(fn func(e T) error) string {
+  return h.Bind(&hook.Handler[T]{Func: fn})
+}
+
+// Unbind removes the handlers with the given ids from the hook.
+func (h *TaggedHook[T]) Unbind(idsToRemove ...string) {
+  h.hook.Unbind(idsToRemove...)
+  h.scope.remove(h.name, idsToRemove...)
+}
+
+// Unwrap returns the pocketbase hook, e.g. to pass it to a function which expects one. Handlers bound to it directly
+// are not recorded.
+func (h *TaggedHook[T]) Unwrap() *hook.TaggedHook[T] {
+  return h.hook
+}
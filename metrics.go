@@ -0,0 +1,210 @@
+package pocketframework
+
+import (
+  "fmt"
+  "io"
+  "net/http"
+  "sort"
+  "strconv"
+  "strings"
+  "sync"
+  "time"
+
+  "github.com/pocketbase/pocketbase/core"
+  "github.com/pocketbase/pocketbase/tools/hook"
+  "github.com/pocketbase/pocketbase/tools/router"
+)
+
+// DefaultMetricsBuckets are the upper bounds in seconds of the latency histograms, the same as the Prometheus
+// client defaults.
+var DefaultMetricsBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// metricsPriority makes the route metrics include the time spent in the flag and auth middlewares.
+const metricsPriority = moduleFlagPriority - 1
+
+// Metrics collects the request and hook metrics of all modules and exposes them in the Prometheus text format.
+type Metrics struct {
+  mu       sync.Mutex
+  families map[string]*metricFamily
+}
+
+type metricFamily struct {
+  name    string
+  help    string
+  typ     string
+  buckets []float64
+  series  map[string]*metricSeries
+}
+
+type metricSeries struct {
+  labels string
+  value  float64
+  counts []uint64
+  sum    float64
+  count  uint64
+}
+
+func newMetrics() *Metrics {
+  metrics := &Metrics{families: map[string]*metricFamily{}}
+
+  metrics.register("pocketframework_http_requests_total", "Number of handled requests.", "counter")
+  metrics.register("pocketframework_http_request_duration_seconds", "Duration of handled requests.", "histogram")
+  metrics.register("pocketframework_hook_invocations_total", "Number of hook handler invocations.", "counter")
+  metrics.register("pocketframework_hook_errors_total", "Number of hook handler invocations which returned an error.", "counter")
+  metrics.register("pocketframework_hook_duration_seconds", "Duration of hook handler invocations, including the handlers they call with e.Next().", "histogram")
+
+  return metrics
+}
+
+// Metrics returns the metrics of the registry.
+func (m *ModuleRegistry) Metrics() *Metrics {
+  return m.metrics
+}
+
+// WriteTo writes all metrics in the Prometheus text exposition format.
+func (r *Metrics) WriteTo(w io.Writer) (int64, error) {
+  r.mu.Lock()
+  defer r.mu.Unlock()
+
+  var builder strings.Builder
+
+  names := make([]string, 0, len(r.families))
+  for name := range r.families {
+    names = append(names, name)
+  }
+  sort.Strings(names)
+
+  for _, name := range names {
+    family := r.families[name]
+    fmt.Fprintf(&builder, "# HELP %s %s\n# TYPE %s %s\n", family.name, family.help, family.name, family.typ)
+
+    keys := make([]string, 0, len(family.series))
+    for key := range family.series {
+      keys = append(keys, key)
+    }
+    sort.Strings(keys)
+
+    for _, key := range keys {
+      series := family.series[key]
+      if family.typ == "counter" {
+        fmt.Fprintf(&builder, "%s{%s} %s\n", family.name, series.labels, formatMetricValue(series.value))
+        continue
+      }
+
+      var cumulative uint64
+      for i, bound := range family.buckets {
+        cumulative += series.counts[i]
+        fmt.Fprintf(&builder, "%s_bucket{%s,le=\"%s\"} %d\n", family.name, series.labels, formatMetricValue(bound), cumulative)
+      }
+      fmt.Fprintf(&builder, "%s_bucket{%s,le=\"+Inf\"} %d\n", family.name, series.labels, series.count)
+      fmt.Fprintf(&builder, "%s_sum{%s} %s\n", family.name, series.labels, formatMetricValue(series.sum))
+      fmt.Fprintf(&builder, "%s_count{%s} %d\n", family.name, series.labels, series.count)
+    }
+  }
+
+  n, err := io.WriteString(w, builder.String())
+  return int64(n), err
+}
+
+func (r *Metrics) register(name string, help string, typ string) {
+  family := &metricFamily{name: name, help: help, typ: typ, series: map[string]*metricSeries{}}
+  if typ == "histogram" {
+    family.buckets = DefaultMetricsBuckets
+  }
+
+  r.families[name] = family
+}
+
+// add increments a counter. labels are pairs of label names and values.
+func (r *Metrics) add(name string, value float64, labels ...string) {
+  r.mu.Lock()
+  defer r.mu.Unlock()
+
+  r.series(name, labels).value += value
+}
+
+// observe records a value in a histogram. labels are pairs of label names and values.
+func (r *Metrics) observe(name string, value float64, labels ...string) {
+  r.mu.Lock()
+  defer r.mu.Unlock()
+
+  family := r.families[name]
+  series := r.series(name, labels)
+  for i, bound := range family.buckets {
+    if value <= bound {
+      series.counts[i]++
+      break
+    }
+  }
+  series.sum += value
+  series.count++
+}
+
+// series returns the series with the given labels, creating it on first use. The caller must hold the lock.
+func (r *Metrics) series(name string, labels []string) *metricSeries {
+  family := r.families[name]
+
+  pairs := make([]string, 0, len(labels)/2)
+  for i := 0; i+1 < len(labels); i += 2 {
+    pairs = append(pairs, labels[i]+"=\""+escapeLabelValue(labels[i+1])+"\"")
+  }
+  key := strings.Join(pairs, ",")
+
+  series, ok := family.series[key]
+  if !ok {
+    series = &metricSeries{labels: key, counts: make([]uint64, len(family.buckets))}
+    family.series[key] = series
+  }
+
+  return series
+}
+
+// routeMetricsMiddleware records the count, status class and latency of the requests to a single route.
+func (r *Metrics) routeMetricsMiddleware(info RouteInfo) *hook.Handler[*core.RequestEvent] {
+  return &hook.Handler[*core.RequestEvent]{
+    Priority: metricsPriority,
+    Func: func(e *core.RequestEvent) error {
+      started := time.Now()
+      err := e.Next()
+
+      status := e.Status()
+      if err != nil {
+        status = router.ToApiError(err).Status
+      }
+      if status == 0 {
+        status = http.StatusOK
+      }
+
+      labels := []string{"module", info.Module, "group", info.Group, "method", info.Method, "route", info.Path}
+      r.add("pocketframework_http_requests_total", 1, append(labels, "status_class", strconv.Itoa(status/100)+"xx")...)
+      r.observe("pocketframework_http_request_duration_seconds", time.Since(started).Seconds(), labels...)
+
+      return err
+    },
+  }
+}
+
+// observeHook records a single invocation of a hook handler of a module.
+func (r *Metrics) observeHook(module string, hookName string, duration time.Duration, err error) {
+  r.add("pocketframework_hook_invocations_total", 1, "module", module, "hook", hookName)
+  if err != nil {
+    r.add("pocketframework_hook_errors_total", 1, "module", module, "hook", hookName)
+  }
+  r.observe("pocketframework_hook_duration_seconds", duration.Seconds(), "module", module, "hook", hookName)
+}
+
+func (m *ModuleRegistry) serveMetrics(e *core.RequestEvent) error {
+  e.Response.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+  e.Response.WriteHeader(http.StatusOK)
+
+  _, err := m.metrics.WriteTo(e.Response)
+  return err
+}
+
+func formatMetricValue(value float64) string {
+  return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func escapeLabelValue(value string) string {
+  return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
@@ -0,0 +1,107 @@
+package pocketframework
+
+import (
+  "errors"
+  "net/http"
+  "net/http/httptest"
+  "strings"
+  "testing"
+  "time"
+
+  "github.com/pocketbase/pocketbase/core"
+)
+
+func TestMetricsOutput(t *testing.T) {
+  metrics := newMetrics()
+
+  metrics.observeHook("billing", "OnRecordCreate", 3*time.Millisecond, nil)
+  metrics.observeHook("billing", "OnRecordCreate", 200*time.Millisecond, errors.New("failed"))
+  metrics.observeHook("billing", "OnRecordCreate", 20*time.Second, nil)
+  metrics.add("pocketframework_http_requests_total", 1, "module", `say "hi"`, "route", "/a\\b\n")
+
+  var output strings.Builder
+  if _, err := metrics.WriteTo(&output); err != nil {
+    t.Fatal(err)
+  }
+
+  expected := []string{
+    "# HELP pocketframework_hook_duration_seconds Duration of hook handler invocations, including the handlers they call with e.Next().",
+    "# TYPE pocketframework_hook_duration_seconds histogram",
+    `pocketframework_hook_duration_seconds_bucket{module="billing",hook="OnRecordCreate",le="0.005"} 1`,
+    `pocketframework_hook_duration_seconds_bucket{module="billing",hook="OnRecordCreate",le="0.1"} 1`,
+    `pocketframework_hook_duration_seconds_bucket{module="billing",hook="OnRecordCreate",le="0.25"} 2`,
+    `pocketframework_hook_duration_seconds_bucket{module="billing",hook="OnRecordCreate",le="10"} 2`,
+    `pocketframework_hook_duration_seconds_bucket{module="billing",hook="OnRecordCreate",le="+Inf"} 3`,
+    `pocketframework_hook_duration_seconds_sum{module="billing",hook="OnRecordCreate"} 20.203`,
+    `pocketframework_hook_duration_seconds_count{module="billing",hook="OnRecordCreate"} 3`,
+    "# TYPE pocketframework_hook_errors_total counter",
+    `pocketframework_hook_errors_total{module="billing",hook="OnRecordCreate"} 1`,
+    `pocketframework_hook_invocations_total{module="billing",hook="OnRecordCreate"} 3`,
+    `pocketframework_http_requests_total{module="say \"hi\"",route="/a\\b\n"} 1`,
+  }
+  for _, line := range expected {
+    if !strings.Contains(output.String(), line+"\n") {
+      t.Errorf("expected the line %q in the output:\n%s", line, output.String())
+    }
+  }
+
+  // the families are sorted by name
+  if hooks, requests := strings.Index(output.String(), "pocketframework_hook_"), strings.Index(output.String(), "pocketframework_http_"); hooks > requests {
+    t.Errorf("expected the families to be sorted by name:\n%s", output.String())
+  }
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+  registry, app := newTestRegistry(
+    t, &testModule{
+      name:   "billing",
+      prefix: "/billing",
+      routes: func(groups RouterGroups) error {
+        groups.Public.GET(
+          "/invoices/{id}", func(e *core.RequestEvent) error {
+            if e.Request.PathValue("id") == "missing" {
+              return e.NotFoundError("", nil)
+            }
+            return e.NoContent(http.StatusNoContent)
+          },
+        )
+        return nil
+      },
+    },
+  )
+  handler := serveTestRegistry(t, registry, app)
+
+  for _, path := range []string{"/api/billing/invoices/1", "/api/billing/invoices/2", "/api/billing/invoices/missing"} {
+    handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+  }
+
+  request := httptest.NewRequest(http.MethodGet, "/api"+FrameworkRoutesPrefix+"/metrics", nil)
+  request.Header.Set("Authorization", newTestSuperuserToken(t, app))
+  recorder := httptest.NewRecorder()
+  handler.ServeHTTP(recorder, request)
+
+  if recorder.Code != http.StatusOK {
+    t.Fatalf("expected status %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body)
+  }
+  if contentType := recorder.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/plain; version=0.0.4") {
+    t.Errorf("expected the Prometheus content type, got %q", contentType)
+  }
+
+  labels := `module="billing",group="public",method="GET",route="/api/billing/invoices/{id}"`
+  expected := []string{
+    `pocketframework_http_requests_total{` + labels + `,status_class="2xx"} 2`,
+    `pocketframework_http_requests_total{` + labels + `,status_class="4xx"} 1`,
+    `pocketframework_http_request_duration_seconds_count{` + labels + `} 3`,
+  }
+  for _, line := range expected {
+    if !strings.Contains(recorder.Body.String(), line+"\n") {
+      t.Errorf("expected the line %q in the output:\n%s", line, recorder.Body)
+    }
+  }
+
+  guest := httptest.NewRecorder()
+  handler.ServeHTTP(guest, httptest.NewRequest(http.MethodGet, "/api"+FrameworkRoutesPrefix+"/metrics", nil))
+  if guest.Code != http.StatusUnauthorized {
+    t.Errorf("expected the metrics to require a superuser, got status %d", guest.Code)
+  }
+}
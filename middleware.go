@@ -0,0 +1,32 @@
+package pocketframework
+
+import (
+  "github.com/pocketbase/pocketbase/core"
+  "github.com/pocketbase/pocketbase/tools/hook"
+)
+
+// Middlewares are executed in the order of their priority. The pocketbase middlewares which load the auth token run
+// with a negative priority, the auth checks of the Authenticated and Admin groups run with priority 0 and are bound
+// before any module middleware.
+const (
+  // MiddlewarePriorityBeforeAuth runs a middleware after the auth token has been loaded but before the auth checks
+  // of the group.
+  MiddlewarePriorityBeforeAuth = -1
+
+  // MiddlewarePriorityAfterAuth runs a middleware after the auth checks of the group. This is the default.
+  MiddlewarePriorityAfterAuth = 0
+)
+
+// BeforeAuth makes a middleware run before the auth checks of the group, e.g. for rate limiting or logging of
+// unauthorized requests. e.Auth is already populated if the request has a valid token. The given handler is not
+// modified, BeforeAuth returns a copy.
+func BeforeAuth(middleware *hook.Handler[*core.RequestEvent]) *hook.Handler[*core.RequestEvent] {
+  beforeAuth := *middleware
+  beforeAuth.Priority = MiddlewarePriorityBeforeAuth
+  return &beforeAuth
+}
+
+// BeforeAuthFunc is like BeforeAuth for a plain middleware function.
+func BeforeAuthFunc(fn func(e *core.RequestEvent) error) *hook.Handler[*core.RequestEvent] {
+  return BeforeAuth(&hook.Handler[*core.RequestEvent]{Func: fn})
+}
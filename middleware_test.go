@@ -0,0 +1,98 @@
+package pocketframework
+
+import (
+  "net/http"
+  "net/http/httptest"
+  "strings"
+  "testing"
+
+  "github.com/pocketbase/pocketbase/core"
+  "github.com/pocketbase/pocketbase/tools/hook"
+)
+
+func TestBeforeAuthCopiesTheHandler(t *testing.T) {
+  middleware := &hook.Handler[*core.RequestEvent]{Id: "audit", Func: func(e *core.RequestEvent) error { return e.Next() }}
+
+  beforeAuth := BeforeAuth(middleware)
+
+  if beforeAuth == middleware {
+    t.Fatal("expected a copy of the handler")
+  }
+  if beforeAuth.Priority != MiddlewarePriorityBeforeAuth || beforeAuth.Id != "audit" || beforeAuth.Func == nil {
+    t.Fatalf("unexpected handler %+v", beforeAuth)
+  }
+  if middleware.Priority != 0 {
+    t.Fatalf("expected the priority of the given handler to be unchanged, got %d", middleware.Priority)
+  }
+}
+
+func TestBeforeAuthOrder(t *testing.T) {
+  calls := []string{}
+  record := func(name string) func(e *core.RequestEvent) error {
+    return func(e *core.RequestEvent) error {
+      if e.Auth != nil {
+        name += " (auth)"
+      }
+      calls = append(calls, name)
+      return e.Next()
+    }
+  }
+
+  registry, app := newTestRegistry(
+    t, &testModule{
+      name:   "billing",
+      prefix: "/billing",
+      routes: func(groups RouterGroups) error {
+        invoices := groups.Authenticated.Group("/invoices")
+        invoices.BindFunc(record("after auth"))
+        invoices.Bind(BeforeAuthFunc(record("before auth")))
+        invoices.GET("", record("action"))
+        return nil
+      },
+    },
+  )
+  handler := serveTestRegistry(t, registry, app)
+
+  token := newTestSuperuserToken(t, app)
+
+  tests := []struct {
+    name   string
+    token  string
+    status int
+    calls  []string
+  }{
+    {
+      name:   "guest",
+      status: http.StatusUnauthorized,
+      calls:  []string{"before auth"},
+    },
+    {
+      name:   "authenticated",
+      token:  token,
+      status: http.StatusOK,
+      calls:  []string{"before auth (auth)", "after auth (auth)", "action (auth)"},
+    },
+  }
+
+  for _, test := range tests {
+    t.Run(
+      test.name, func(t *testing.T) {
+        calls = []string{}
+
+        request := httptest.NewRequest(http.MethodGet, "/api/billing/invoices", nil)
+        if test.token != "" {
+          request.Header.Set("Authorization", test.token)
+        }
+        recorder := httptest.NewRecorder()
+        handler.ServeHTTP(recorder, request)
+
+        if recorder.Code != test.status {
+          t.Fatalf("expected status %d, got %d: %s", test.status, recorder.Code, recorder.Body)
+        }
+        if strings.Join(calls, ",") != strings.Join(test.calls, ",") {
+          t.Fatalf("expected the calls %v, got %v", test.calls, calls)
+        }
+      },
+    )
+  }
+}
@@ -1,8 +1,11 @@
 package pocketframework
 
 import (
+  "context"
+
   "github.com/pocketbase/pocketbase/core"
   "github.com/pocketbase/pocketbase/tools/hook"
+  "github.com/spf13/cobra"
 )
 
 type Module interface {
@@ -21,24 +24,163 @@ type ModuleWithChildren interface {
   Children() []Module
 }
 
+type ModuleWithName interface {
+  Module
+
+  // Name should return the identity of this module. It must be unique among its siblings. Modules without a name are
+  // identified by their prefix, modules without a prefix by their type. Modules which others depend on must have a
+  // name.
+  Name() string
+}
+
+type ModuleWithMetadata interface {
+  Module
+
+  // Metadata should return the descriptive information about this module shown in the module inventory.
+  Metadata() ModuleMetadata
+}
+
+type ModuleWithDependencies interface {
+  Module
+
+  // DependsOn should return the paths of the modules this module relies on, e.g. "users" or "billing/invoices".
+  // Dependencies are initialized, migrated and started before this module. A module cannot depend on its own
+  // children, as parents are always initialized before their children.
+  DependsOn() []string
+}
+
+type ModuleWithMigrations interface {
+  Module
+
+  // Migrations should return the migrations of this module in the order they should be applied. Migration names must
+  // be unique within the module.
+  Migrations() []Migration
+}
+
+type ModuleWithCollections interface {
+  Module
+
+  // Collections should return the collections this module depends on, e.g. created with core.NewBaseCollection.
+  // They are created or updated when the app is bootstrapped, see ModuleRegistry.SyncSchema. Collections should
+  // return new collections on every call and collection names must be unique across all modules.
+  Collections() []*core.Collection
+}
+
+type ModuleWithConfig interface {
+  Module
+
+  // ConfigTarget should return a pointer to the config struct of this module. The registry populates and validates
+  // it before any hooks are registered. Embed ModuleConfig to implement it.
+  ConfigTarget() any
+}
+
+type ServiceProvider interface {
+  Module
+
+  // ProvideServices is called during Init in dependency order, before any hooks are registered.
+  ProvideServices(services *ServiceContainer) error
+}
+
+type ServiceConsumer interface {
+  Module
+
+  // RequiredServices should return the keys of all services this module resolves. Init fails if one of them is not
+  // provided by any module.
+  RequiredServices() []ServiceKey
+
+  // ResolveServices is called during Init in dependency order, once all services have been provided and before any
+  // hooks are registered. The module should keep the services it needs, e.g. with Resolve[*Queue](services).
+  ResolveServices(services ServiceResolver) error
+}
+
+type ModuleWithMiddleware interface {
+  Module
+
+  // Middlewares should return the middlewares which are bound to all groups of this module, including the routes of
+  // its children. They run after the auth middleware of the group unless wrapped with BeforeAuth.
+  Middlewares() []*hook.Handler[*core.RequestEvent]
+}
+
+type ModuleWithJobs interface {
+  Module
+
+  // Jobs should return the periodic jobs of this module. They are scheduled once all modules have been started.
+  // Job names must be unique within the module.
+  Jobs() []Job
+}
+
+type ModuleWithQueueHandlers interface {
+  Module
+
+  // QueueHandlers should return the handlers of the queue job types owned by this module. Job types must be unique
+  // across all modules.
+  QueueHandlers() []QueueHandler
+}
+
+type ModuleWithCommands interface {
+  Module
+
+  // Commands should return the CLI commands of this module. They are mounted under the path of the module, e.g.
+  // "app billing recompute", and app is bootstrapped and the modules are started by the time a command runs.
+  Commands(app core.App) []*cobra.Command
+}
+
+type ModuleWithSettings interface {
+  Module
+
+  // SettingsTarget should return the runtime settings of this module, usually by embedding ModuleSettings.
+  SettingsTarget() SettingsTarget
+}
+
+type ModuleWithHealthCheck interface {
+  Module
+
+  // HealthCheck should return an error if a dependency of this module does not work, e.g. its cache cannot be
+  // reached. ctx expires after the health check timeout of the registry.
+  HealthCheck(ctx context.Context) error
+}
+
+type Starter interface {
+  Module
+
+  // Start is called once the app has been bootstrapped. Modules are started in dependency order and ctx is cancelled
+  // when the app terminates.
+  Start(ctx context.Context) error
+}
+
+type Stopper interface {
+  Module
+
+  // Stop is called when the app terminates. Modules are stopped in reverse start order and ctx expires after the
+  // stop timeout of the registry.
+  Stop(ctx context.Context) error
+}
+
+// ModuleAppHooks are the hooks of the app as seen by a module. They mirror the hooks of core.App, but return *Hook and
+// *TaggedHook instead of the pocketbase hooks, so the registry can record every handler a module binds and remove them
+// again, see ModuleRegistry.Hooks and ModuleRegistry.UnbindHooks. Both offer the methods of the pocketbase hooks, and
+// Unwrap returns the pocketbase hook itself where one is required.
+//
+// core.App therefore does not implement ModuleAppHooks. Wrap it with NewScopedHooks to call RegisterHooks directly,
+// e.g. in tests.
 type ModuleAppHooks interface {
   // OnBootstrap hook is triggered when initializing the main application
   // resources (db, app settings, etc).
-  OnBootstrap() *hook.Hook[*core.BootstrapEvent]
+  OnBootstrap() *Hook[*core.BootstrapEvent]
 
   // OnTerminate hook is triggered when the app is in the process
   // of being terminated (ex. on SIGTERM signal).
   //
   // Note that the app could be terminated abruptly without awaiting the hook completion.
-  OnTerminate() *hook.Hook[*core.TerminateEvent]
+  OnTerminate() *Hook[*core.TerminateEvent]
 
   // OnBackupCreate hook is triggered on each [App.CreateBackup] call.
-  OnBackupCreate() *hook.Hook[*core.BackupEvent]
+  OnBackupCreate() *Hook[*core.BackupEvent]
 
   // OnBackupRestore hook is triggered before app backup restore (aka. [App.RestoreBackup] call).
   //
   // Note that by default on success the application is restarted and the after state of the hook is ignored.
-  OnBackupRestore() *hook.Hook[*core.BackupEvent]
+  OnBackupRestore() *Hook[*core.BackupEvent]
 
   // ---------------------------------------------------------------
   // DB models event hooks
@@ -53,7 +195,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection id/name, Model table name, etc.) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnModelValidate(tags ...string) *hook.TaggedHook[*core.ModelEvent]
+  OnModelValidate(tags ...string) *TaggedHook[*core.ModelEvent]
 
   // ---------------------------------------------------------------
 
@@ -78,7 +220,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection id/name, Model table name, etc.) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnModelCreate(tags ...string) *hook.TaggedHook[*core.ModelEvent]
+  OnModelCreate(tags ...string) *TaggedHook[*core.ModelEvent]
 
   // OnModelCreateExecute is triggered after successful Model validation
   // and right before the model INSERT DB statement execution.
@@ -101,7 +243,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection id/name, Model table name, etc.) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnModelCreateExecute(tags ...string) *hook.TaggedHook[*core.ModelEvent]
+  OnModelCreateExecute(tags ...string) *TaggedHook[*core.ModelEvent]
 
   // OnModelAfterCreateSuccess is triggered after each successful
   // Model DB create persistence.
@@ -117,7 +259,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection id/name, Model table name, etc.) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnModelAfterCreateSuccess(tags ...string) *hook.TaggedHook[*core.ModelEvent]
+  OnModelAfterCreateSuccess(tags ...string) *TaggedHook[*core.ModelEvent]
 
   // OnModelAfterCreateError is triggered after each failed
   // Model DB create persistence.
@@ -133,7 +275,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection id/name, Model table name, etc.) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnModelAfterCreateError(tags ...string) *hook.TaggedHook[*core.ModelErrorEvent]
+  OnModelAfterCreateError(tags ...string) *TaggedHook[*core.ModelErrorEvent]
 
   // ---------------------------------------------------------------
 
@@ -158,7 +300,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection id/name, Model table name, etc.) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnModelUpdate(tags ...string) *hook.TaggedHook[*core.ModelEvent]
+  OnModelUpdate(tags ...string) *TaggedHook[*core.ModelEvent]
 
   // OnModelUpdateExecute is triggered after successful Model validation
   // and right before the model UPDATE DB statement execution.
@@ -181,7 +323,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection id/name, Model table name, etc.) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnModelUpdateExecute(tags ...string) *hook.TaggedHook[*core.ModelEvent]
+  OnModelUpdateExecute(tags ...string) *TaggedHook[*core.ModelEvent]
 
   // OnModelAfterUpdateSuccess is triggered after each successful
   // Model DB update persistence.
@@ -197,7 +339,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection id/name, Model table name, etc.) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnModelAfterUpdateSuccess(tags ...string) *hook.TaggedHook[*core.ModelEvent]
+  OnModelAfterUpdateSuccess(tags ...string) *TaggedHook[*core.ModelEvent]
 
   // OnModelAfterUpdateError is triggered after each failed
   // Model DB update persistence.
@@ -213,7 +355,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection id/name, Model table name, etc.) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnModelAfterUpdateError(tags ...string) *hook.TaggedHook[*core.ModelErrorEvent]
+  OnModelAfterUpdateError(tags ...string) *TaggedHook[*core.ModelErrorEvent]
 
   // ---------------------------------------------------------------
 
@@ -232,7 +374,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection id/name, Model table name, etc.) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnModelDelete(tags ...string) *hook.TaggedHook[*core.ModelEvent]
+  OnModelDelete(tags ...string) *TaggedHook[*core.ModelEvent]
 
   // OnModelUpdateExecute is triggered right before the model
   // DELETE DB statement execution.
@@ -255,7 +397,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection id/name, Model table name, etc.) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnModelDeleteExecute(tags ...string) *hook.TaggedHook[*core.ModelEvent]
+  OnModelDeleteExecute(tags ...string) *TaggedHook[*core.ModelEvent]
 
   // OnModelAfterDeleteSuccess is triggered after each successful
   // Model DB delete persistence.
@@ -271,7 +413,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection id/name, Model table name, etc.) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnModelAfterDeleteSuccess(tags ...string) *hook.TaggedHook[*core.ModelEvent]
+  OnModelAfterDeleteSuccess(tags ...string) *TaggedHook[*core.ModelEvent]
 
   // OnModelAfterDeleteError is triggered after each failed
   // Model DB delete persistence.
@@ -287,7 +429,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection id/name, Model table name, etc.) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnModelAfterDeleteError(tags ...string) *hook.TaggedHook[*core.ModelErrorEvent]
+  OnModelAfterDeleteError(tags ...string) *TaggedHook[*core.ModelErrorEvent]
 
   // ---------------------------------------------------------------
   // Record models event hooks
@@ -315,14 +457,14 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordEnrich(tags ...string) *hook.TaggedHook[*core.RecordEnrichEvent]
+  OnRecordEnrich(tags ...string) *TaggedHook[*core.RecordEnrichEvent]
 
   // OnRecordValidate is a Record proxy model hook of [OnModelValidate].
   //
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordValidate(tags ...string) *hook.TaggedHook[*core.RecordEvent]
+  OnRecordValidate(tags ...string) *TaggedHook[*core.RecordEvent]
 
   // ---------------------------------------------------------------
 
@@ -331,28 +473,28 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordCreate(tags ...string) *hook.TaggedHook[*core.RecordEvent]
+  OnRecordCreate(tags ...string) *TaggedHook[*core.RecordEvent]
 
   // OnRecordCreateExecute is a Record proxy model hook of [OnModelCreateExecute].
   //
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordCreateExecute(tags ...string) *hook.TaggedHook[*core.RecordEvent]
+  OnRecordCreateExecute(tags ...string) *TaggedHook[*core.RecordEvent]
 
   // OnRecordAfterCreateSuccess is a Record proxy model hook of [OnModelAfterCreateSuccess].
   //
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordAfterCreateSuccess(tags ...string) *hook.TaggedHook[*core.RecordEvent]
+  OnRecordAfterCreateSuccess(tags ...string) *TaggedHook[*core.RecordEvent]
 
   // OnRecordAfterCreateError is a Record proxy model hook of [OnModelAfterCreateError].
   //
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordAfterCreateError(tags ...string) *hook.TaggedHook[*core.RecordErrorEvent]
+  OnRecordAfterCreateError(tags ...string) *TaggedHook[*core.RecordErrorEvent]
 
   // ---------------------------------------------------------------
 
@@ -361,28 +503,28 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordUpdate(tags ...string) *hook.TaggedHook[*core.RecordEvent]
+  OnRecordUpdate(tags ...string) *TaggedHook[*core.RecordEvent]
 
   // OnRecordUpdateExecute is a Record proxy model hook of [OnModelUpdateExecute].
   //
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordUpdateExecute(tags ...string) *hook.TaggedHook[*core.RecordEvent]
+  OnRecordUpdateExecute(tags ...string) *TaggedHook[*core.RecordEvent]
 
   // OnRecordAfterUpdateSuccess is a Record proxy model hook of [OnModelAfterUpdateSuccess].
   //
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordAfterUpdateSuccess(tags ...string) *hook.TaggedHook[*core.RecordEvent]
+  OnRecordAfterUpdateSuccess(tags ...string) *TaggedHook[*core.RecordEvent]
 
   // OnRecordAfterUpdateError is a Record proxy model hook of [OnModelAfterUpdateError].
   //
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordAfterUpdateError(tags ...string) *hook.TaggedHook[*core.RecordErrorEvent]
+  OnRecordAfterUpdateError(tags ...string) *TaggedHook[*core.RecordErrorEvent]
 
   // ---------------------------------------------------------------
 
@@ -391,28 +533,28 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordDelete(tags ...string) *hook.TaggedHook[*core.RecordEvent]
+  OnRecordDelete(tags ...string) *TaggedHook[*core.RecordEvent]
 
   // OnRecordDeleteExecute is a Record proxy model hook of [OnModelDeleteExecute].
   //
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordDeleteExecute(tags ...string) *hook.TaggedHook[*core.RecordEvent]
+  OnRecordDeleteExecute(tags ...string) *TaggedHook[*core.RecordEvent]
 
   // OnRecordAfterDeleteSuccess is a Record proxy model hook of [OnModelAfterDeleteSuccess].
   //
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordAfterDeleteSuccess(tags ...string) *hook.TaggedHook[*core.RecordEvent]
+  OnRecordAfterDeleteSuccess(tags ...string) *TaggedHook[*core.RecordEvent]
 
   // OnRecordAfterDeleteError is a Record proxy model hook of [OnModelAfterDeleteError].
   //
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordAfterDeleteError(tags ...string) *hook.TaggedHook[*core.RecordErrorEvent]
+  OnRecordAfterDeleteError(tags ...string) *TaggedHook[*core.RecordErrorEvent]
 
   // ---------------------------------------------------------------
   // Collection models event hooks
@@ -423,7 +565,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnCollectionValidate(tags ...string) *hook.TaggedHook[*core.CollectionEvent]
+  OnCollectionValidate(tags ...string) *TaggedHook[*core.CollectionEvent]
 
   // ---------------------------------------------------------------
 
@@ -432,28 +574,28 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnCollectionCreate(tags ...string) *hook.TaggedHook[*core.CollectionEvent]
+  OnCollectionCreate(tags ...string) *TaggedHook[*core.CollectionEvent]
 
   // OnCollectionCreateExecute is a Collection proxy model hook of [OnModelCreateExecute].
   //
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnCollectionCreateExecute(tags ...string) *hook.TaggedHook[*core.CollectionEvent]
+  OnCollectionCreateExecute(tags ...string) *TaggedHook[*core.CollectionEvent]
 
   // OnCollectionAfterCreateSuccess is a Collection proxy model hook of [OnModelAfterCreateSuccess].
   //
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnCollectionAfterCreateSuccess(tags ...string) *hook.TaggedHook[*core.CollectionEvent]
+  OnCollectionAfterCreateSuccess(tags ...string) *TaggedHook[*core.CollectionEvent]
 
   // OnCollectionAfterCreateError is a Collection proxy model hook of [OnModelAfterCreateError].
   //
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnCollectionAfterCreateError(tags ...string) *hook.TaggedHook[*core.CollectionErrorEvent]
+  OnCollectionAfterCreateError(tags ...string) *TaggedHook[*core.CollectionErrorEvent]
 
   // ---------------------------------------------------------------
 
@@ -462,28 +604,28 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnCollectionUpdate(tags ...string) *hook.TaggedHook[*core.CollectionEvent]
+  OnCollectionUpdate(tags ...string) *TaggedHook[*core.CollectionEvent]
 
   // OnCollectionUpdateExecute is a Collection proxy model hook of [OnModelUpdateExecute].
   //
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnCollectionUpdateExecute(tags ...string) *hook.TaggedHook[*core.CollectionEvent]
+  OnCollectionUpdateExecute(tags ...string) *TaggedHook[*core.CollectionEvent]
 
   // OnCollectionAfterUpdateSuccess is a Collection proxy model hook of [OnModelAfterUpdateSuccess].
   //
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnCollectionAfterUpdateSuccess(tags ...string) *hook.TaggedHook[*core.CollectionEvent]
+  OnCollectionAfterUpdateSuccess(tags ...string) *TaggedHook[*core.CollectionEvent]
 
   // OnCollectionAfterUpdateError is a Collection proxy model hook of [OnModelAfterUpdateError].
   //
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnCollectionAfterUpdateError(tags ...string) *hook.TaggedHook[*core.CollectionErrorEvent]
+  OnCollectionAfterUpdateError(tags ...string) *TaggedHook[*core.CollectionErrorEvent]
 
   // ---------------------------------------------------------------
 
@@ -492,28 +634,28 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnCollectionDelete(tags ...string) *hook.TaggedHook[*core.CollectionEvent]
+  OnCollectionDelete(tags ...string) *TaggedHook[*core.CollectionEvent]
 
   // OnCollectionDeleteExecute is a Collection proxy model hook of [OnModelDeleteExecute].
   //
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnCollectionDeleteExecute(tags ...string) *hook.TaggedHook[*core.CollectionEvent]
+  OnCollectionDeleteExecute(tags ...string) *TaggedHook[*core.CollectionEvent]
 
   // OnCollectionAfterDeleteSuccess is a Collection proxy model hook of [OnModelAfterDeleteSuccess].
   //
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnCollectionAfterDeleteSuccess(tags ...string) *hook.TaggedHook[*core.CollectionEvent]
+  OnCollectionAfterDeleteSuccess(tags ...string) *TaggedHook[*core.CollectionEvent]
 
   // OnCollectionAfterDeleteError is a Collection proxy model hook of [OnModelAfterDeleteError].
   //
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnCollectionAfterDeleteError(tags ...string) *hook.TaggedHook[*core.CollectionErrorEvent]
+  OnCollectionAfterDeleteError(tags ...string) *TaggedHook[*core.CollectionErrorEvent]
 
   // ---------------------------------------------------------------
   // Mailer event hooks
@@ -523,7 +665,7 @@ type ModuleAppHooks interface {
   // being send using the [App.NewMailClient()] instance.
   //
   // It allows intercepting the email message or to use a custom mailer client.
-  OnMailerSend() *hook.Hook[*core.MailerEvent]
+  OnMailerSend() *Hook[*core.MailerEvent]
 
   // OnMailerRecordAuthAlertSend hook is triggered when
   // sending a new device login auth alert email, allowing you to
@@ -532,7 +674,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnMailerRecordAuthAlertSend(tags ...string) *hook.TaggedHook[*core.MailerRecordEvent]
+  OnMailerRecordAuthAlertSend(tags ...string) *TaggedHook[*core.MailerRecordEvent]
 
   // OnMailerRecordPasswordResetSend hook is triggered when
   // sending a password reset email to an auth record, allowing
@@ -541,7 +683,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnMailerRecordPasswordResetSend(tags ...string) *hook.TaggedHook[*core.MailerRecordEvent]
+  OnMailerRecordPasswordResetSend(tags ...string) *TaggedHook[*core.MailerRecordEvent]
 
   // OnMailerRecordVerificationSend hook is triggered when
   // sending a verification email to an auth record, allowing
@@ -550,7 +692,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnMailerRecordVerificationSend(tags ...string) *hook.TaggedHook[*core.MailerRecordEvent]
+  OnMailerRecordVerificationSend(tags ...string) *TaggedHook[*core.MailerRecordEvent]
 
   // OnMailerRecordEmailChangeSend hook is triggered when sending a
   // confirmation new address email to an auth record, allowing
@@ -559,7 +701,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnMailerRecordEmailChangeSend(tags ...string) *hook.TaggedHook[*core.MailerRecordEvent]
+  OnMailerRecordEmailChangeSend(tags ...string) *TaggedHook[*core.MailerRecordEvent]
 
   // OnMailerRecordOTPSend hook is triggered when sending an OTP email
   // to an auth record, allowing you to intercept and customize the
@@ -568,7 +710,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnMailerRecordOTPSend(tags ...string) *hook.TaggedHook[*core.MailerRecordEvent]
+  OnMailerRecordOTPSend(tags ...string) *TaggedHook[*core.MailerRecordEvent]
 
   // ---------------------------------------------------------------
   // Realtime API event hooks
@@ -577,15 +719,15 @@ type ModuleAppHooks interface {
   // OnRealtimeConnectRequest hook is triggered when establishing the SSE client connection.
   //
   // Any execution after e.Next() of a hook handler happens after the client disconnects.
-  OnRealtimeConnectRequest() *hook.Hook[*core.RealtimeConnectRequestEvent]
+  OnRealtimeConnectRequest() *Hook[*core.RealtimeConnectRequestEvent]
 
   // OnRealtimeMessageSend hook is triggered when sending an SSE message to a client.
-  OnRealtimeMessageSend() *hook.Hook[*core.RealtimeMessageEvent]
+  OnRealtimeMessageSend() *Hook[*core.RealtimeMessageEvent]
 
   // OnRealtimeSubscribeRequest hook is triggered when updating the
   // client subscriptions, allowing you to further validate and
   // modify the submitted change.
-  OnRealtimeSubscribeRequest() *hook.Hook[*core.RealtimeSubscribeRequestEvent]
+  OnRealtimeSubscribeRequest() *Hook[*core.RealtimeSubscribeRequestEvent]
 
   // ---------------------------------------------------------------
   // Settings API event hooks
@@ -594,19 +736,19 @@ type ModuleAppHooks interface {
   // OnSettingsListRequest hook is triggered on each API Settings list request.
   //
   // Could be used to validate or modify the response before returning it to the client.
-  OnSettingsListRequest() *hook.Hook[*core.SettingsListRequestEvent]
+  OnSettingsListRequest() *Hook[*core.SettingsListRequestEvent]
 
   // OnSettingsUpdateRequest hook is triggered on each API Settings update request.
   //
   // Could be used to additionally validate the request data or
   // implement completely different persistence behavior.
-  OnSettingsUpdateRequest() *hook.Hook[*core.SettingsUpdateRequestEvent]
+  OnSettingsUpdateRequest() *Hook[*core.SettingsUpdateRequestEvent]
 
   // OnSettingsReload hook is triggered every time when the App.Settings()
   // is being replaced with a new state.
   //
   // Calling App.Settings() after e.Next() returns the new state.
-  OnSettingsReload() *hook.Hook[*core.SettingsReloadEvent]
+  OnSettingsReload() *Hook[*core.SettingsReloadEvent]
 
   // ---------------------------------------------------------------
   // File API event hooks
@@ -616,14 +758,14 @@ type ModuleAppHooks interface {
   //
   // Could be used to validate or modify the file response before
   // returning it to the client.
-  OnFileDownloadRequest(tags ...string) *hook.TaggedHook[*core.FileDownloadRequestEvent]
+  OnFileDownloadRequest(tags ...string) *TaggedHook[*core.FileDownloadRequestEvent]
 
   // OnFileBeforeTokenRequest hook is triggered on each auth file token API request.
   //
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnFileTokenRequest(tags ...string) *hook.TaggedHook[*core.FileTokenRequestEvent]
+  OnFileTokenRequest(tags ...string) *TaggedHook[*core.FileTokenRequestEvent]
 
   // ---------------------------------------------------------------
   // Record Auth API event hooks
@@ -638,7 +780,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordAuthRequest(tags ...string) *hook.TaggedHook[*core.RecordAuthRequestEvent]
+  OnRecordAuthRequest(tags ...string) *TaggedHook[*core.RecordAuthRequestEvent]
 
   // OnRecordAuthWithPasswordRequest hook is triggered on each
   // Record auth with password API request.
@@ -649,7 +791,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordAuthWithPasswordRequest(tags ...string) *hook.TaggedHook[*core.RecordAuthWithPasswordRequestEvent]
+  OnRecordAuthWithPasswordRequest(tags ...string) *TaggedHook[*core.RecordAuthWithPasswordRequestEvent]
 
   // OnRecordAuthWithOAuth2Request hook is triggered on each Record
   // OAuth2 sign-in/sign-up API request (after token exchange and before external provider linking).
@@ -663,7 +805,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordAuthWithOAuth2Request(tags ...string) *hook.TaggedHook[*core.RecordAuthWithOAuth2RequestEvent]
+  OnRecordAuthWithOAuth2Request(tags ...string) *TaggedHook[*core.RecordAuthWithOAuth2RequestEvent]
 
   // OnRecordAuthRefreshRequest hook is triggered on each Record
   // auth refresh API request (right before generating a new auth token).
@@ -674,7 +816,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordAuthRefreshRequest(tags ...string) *hook.TaggedHook[*core.RecordAuthRefreshRequestEvent]
+  OnRecordAuthRefreshRequest(tags ...string) *TaggedHook[*core.RecordAuthRefreshRequestEvent]
 
   // OnRecordRequestPasswordResetRequest hook is triggered on
   // each Record request password reset API request.
@@ -685,7 +827,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordRequestPasswordResetRequest(tags ...string) *hook.TaggedHook[*core.RecordRequestPasswordResetRequestEvent]
+  OnRecordRequestPasswordResetRequest(tags ...string) *TaggedHook[*core.RecordRequestPasswordResetRequestEvent]
 
   // OnRecordConfirmPasswordResetRequest hook is triggered on
   // each Record confirm password reset API request.
@@ -696,7 +838,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordConfirmPasswordResetRequest(tags ...string) *hook.TaggedHook[*core.RecordConfirmPasswordResetRequestEvent]
+  OnRecordConfirmPasswordResetRequest(tags ...string) *TaggedHook[*core.RecordConfirmPasswordResetRequestEvent]
 
   // OnRecordRequestVerificationRequest hook is triggered on
   // each Record request verification API request.
@@ -707,7 +849,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordRequestVerificationRequest(tags ...string) *hook.TaggedHook[*core.RecordRequestVerificationRequestEvent]
+  OnRecordRequestVerificationRequest(tags ...string) *TaggedHook[*core.RecordRequestVerificationRequestEvent]
 
   // OnRecordConfirmVerificationRequest hook is triggered on each
   // Record confirm verification API request.
@@ -718,7 +860,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordConfirmVerificationRequest(tags ...string) *hook.TaggedHook[*core.RecordConfirmVerificationRequestEvent]
+  OnRecordConfirmVerificationRequest(tags ...string) *TaggedHook[*core.RecordConfirmVerificationRequestEvent]
 
   // OnRecordRequestEmailChangeRequest hook is triggered on each
   // Record request email change API request.
@@ -729,7 +871,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordRequestEmailChangeRequest(tags ...string) *hook.TaggedHook[*core.RecordRequestEmailChangeRequestEvent]
+  OnRecordRequestEmailChangeRequest(tags ...string) *TaggedHook[*core.RecordRequestEmailChangeRequestEvent]
 
   // OnRecordConfirmEmailChangeRequest hook is triggered on each
   // Record confirm email change API request.
@@ -740,7 +882,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordConfirmEmailChangeRequest(tags ...string) *hook.TaggedHook[*core.RecordConfirmEmailChangeRequestEvent]
+  OnRecordConfirmEmailChangeRequest(tags ...string) *TaggedHook[*core.RecordConfirmEmailChangeRequestEvent]
 
   // OnRecordRequestOTPRequest hook is triggered on each Record
   // request OTP API request.
@@ -751,7 +893,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordRequestOTPRequest(tags ...string) *hook.TaggedHook[*core.RecordCreateOTPRequestEvent]
+  OnRecordRequestOTPRequest(tags ...string) *TaggedHook[*core.RecordCreateOTPRequestEvent]
 
   // OnRecordAuthWithOTPRequest hook is triggered on each Record
   // auth with OTP API request.
@@ -759,7 +901,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordAuthWithOTPRequest(tags ...string) *hook.TaggedHook[*core.RecordAuthWithOTPRequestEvent]
+  OnRecordAuthWithOTPRequest(tags ...string) *TaggedHook[*core.RecordAuthWithOTPRequestEvent]
 
   // ---------------------------------------------------------------
   // Record CRUD API event hooks
@@ -772,7 +914,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordsListRequest(tags ...string) *hook.TaggedHook[*core.RecordsListRequestEvent]
+  OnRecordsListRequest(tags ...string) *TaggedHook[*core.RecordsListRequestEvent]
 
   // OnRecordViewRequest hook is triggered on each API Record view request.
   //
@@ -781,7 +923,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordViewRequest(tags ...string) *hook.TaggedHook[*core.RecordRequestEvent]
+  OnRecordViewRequest(tags ...string) *TaggedHook[*core.RecordRequestEvent]
 
   // OnRecordCreateRequest hook is triggered on each API Record create request.
   //
@@ -791,7 +933,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordCreateRequest(tags ...string) *hook.TaggedHook[*core.RecordRequestEvent]
+  OnRecordCreateRequest(tags ...string) *TaggedHook[*core.RecordRequestEvent]
 
   // OnRecordUpdateRequest hook is triggered on each API Record update request.
   //
@@ -801,7 +943,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordUpdateRequest(tags ...string) *hook.TaggedHook[*core.RecordRequestEvent]
+  OnRecordUpdateRequest(tags ...string) *TaggedHook[*core.RecordRequestEvent]
 
   // OnRecordDeleteRequest hook is triggered on each API Record delete request.
   //
@@ -811,7 +953,7 @@ type ModuleAppHooks interface {
   // If the optional "tags" list (Collection ids or names) is specified,
   // then all event handlers registered via the created hook will be
   // triggered and called only if their event data origin matches the tags.
-  OnRecordDeleteRequest(tags ...string) *hook.TaggedHook[*core.RecordRequestEvent]
+  OnRecordDeleteRequest(tags ...string) *TaggedHook[*core.RecordRequestEvent]
 
   // ---------------------------------------------------------------
   // Collection API event hooks
@@ -820,37 +962,37 @@ type ModuleAppHooks interface {
   // OnCollectionsListRequest hook is triggered on each API Collections list request.
   //
   // Could be used to validate or modify the response before returning it to the client.
-  OnCollectionsListRequest() *hook.Hook[*core.CollectionsListRequestEvent]
+  OnCollectionsListRequest() *Hook[*core.CollectionsListRequestEvent]
 
   // OnCollectionViewRequest hook is triggered on each API Collection view request.
   //
   // Could be used to validate or modify the response before returning it to the client.
-  OnCollectionViewRequest() *hook.Hook[*core.CollectionRequestEvent]
+  OnCollectionViewRequest() *Hook[*core.CollectionRequestEvent]
 
   // OnCollectionCreateRequest hook is triggered on each API Collection create request.
   //
   // Could be used to additionally validate the request data or implement
   // completely different persistence behavior.
-  OnCollectionCreateRequest() *hook.Hook[*core.CollectionRequestEvent]
+  OnCollectionCreateRequest() *Hook[*core.CollectionRequestEvent]
 
   // OnCollectionUpdateRequest hook is triggered on each API Collection update request.
   //
   // Could be used to additionally validate the request data or implement
   // completely different persistence behavior.
-  OnCollectionUpdateRequest() *hook.Hook[*core.CollectionRequestEvent]
+  OnCollectionUpdateRequest() *Hook[*core.CollectionRequestEvent]
 
   // OnCollectionDeleteRequest hook is triggered on each API Collection delete request.
   //
   // Could be used to additionally validate the request data or implement
   // completely different delete behavior.
-  OnCollectionDeleteRequest() *hook.Hook[*core.CollectionRequestEvent]
+  OnCollectionDeleteRequest() *Hook[*core.CollectionRequestEvent]
 
   // OnCollectionsImportRequest hook is triggered on each API
   // collections import request.
   //
   // Could be used to additionally validate the imported collections or
   // to implement completely different import behavior.
-  OnCollectionsImportRequest() *hook.Hook[*core.CollectionsImportRequestEvent]
+  OnCollectionsImportRequest() *Hook[*core.CollectionsImportRequestEvent]
 
   // ---------------------------------------------------------------
   // Batch API event hooks
@@ -859,5 +1001,5 @@ type ModuleAppHooks interface {
   // OnBatchRequest hook is triggered on each API batch request.
   //
   // Could be used to additionally validate or modify the submitted batch requests.
-  OnBatchRequest() *hook.Hook[*core.BatchRequestEvent]
+  OnBatchRequest() *Hook[*core.BatchRequestEvent]
 }
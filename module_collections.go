@@ -0,0 +1,405 @@
+package pocketframework
+
+import (
+  "bytes"
+  "database/sql"
+  "encoding/json"
+  "errors"
+  "fmt"
+  "net/http"
+  "reflect"
+  "slices"
+  "strings"
+
+  "github.com/pocketbase/pocketbase/core"
+  "github.com/pocketbase/pocketbase/tools/dbutils"
+)
+
+// SchemaChangeKind is the kind of difference between a declared collection and the database.
+type SchemaChangeKind string
+
+const (
+  SchemaChangeCreateCollection SchemaChangeKind = "createCollection"
+  SchemaChangeCollectionType   SchemaChangeKind = "changeCollectionType"
+  SchemaChangeAddField         SchemaChangeKind = "addField"
+  SchemaChangeUpdateField      SchemaChangeKind = "updateField"
+  SchemaChangeFieldType        SchemaChangeKind = "changeFieldType"
+  SchemaChangeRemoveField      SchemaChangeKind = "removeField"
+  SchemaChangeAddIndex         SchemaChangeKind = "addIndex"
+  SchemaChangeUpdateIndex      SchemaChangeKind = "updateIndex"
+  SchemaChangeRemoveIndex      SchemaChangeKind = "removeIndex"
+  SchemaChangeUpdateRule       SchemaChangeKind = "updateRule"
+  SchemaChangeUpdateViewQuery  SchemaChangeKind = "updateViewQuery"
+)
+
+// SchemaChange is a single difference between a collection declared by a module and the database.
+type SchemaChange struct {
+  Module     string           `json:"module"`
+  Collection string           `json:"collection"`
+  Kind       SchemaChangeKind `json:"kind"`
+
+  // Target is the name of the changed field, index or rule, if any.
+  Target string `json:"target,omitempty"`
+
+  // Destructive changes modify or remove existing fields, indexes, rules or view queries, so they may lose data or
+  // undo changes made by hand. They are only applied if allowed, see WithDestructiveSchemaSync.
+  Destructive bool `json:"destructive"`
+}
+
+func (c SchemaChange) String() string {
+  description := fmt.Sprintf("%s: %s %s", c.Module, c.Kind, c.Collection)
+  if c.Target != "" {
+    description += "." + c.Target
+  }
+  if c.Destructive {
+    description += " (destructive)"
+  }
+
+  return description
+}
+
+// SchemaSyncResult reports the changes applied by a schema sync and the changes which have been skipped.
+type SchemaSyncResult struct {
+  Applied []SchemaChange `json:"applied"`
+  Skipped []SchemaChange `json:"skipped"`
+}
+
+// WithDestructiveSchemaSync allows the schema sync at bootstrap to apply destructive changes, e.g. to update rules or
+// to remove fields which are no longer declared. Changing the type of a collection is never applied automatically.
+func WithDestructiveSchemaSync() RegistryOption {
+  return func(m *ModuleRegistry) {
+    m.destructiveSchemaSync = true
+  }
+}
+
+type collectionDeclaration struct {
+  module     string
+  collection *core.Collection
+}
+
+// SchemaDiff compares the collections declared by the modules with the database and returns every difference.
+func (m *ModuleRegistry) SchemaDiff() ([]SchemaChange, error) {
+  declarations, err := m.collectionDeclarations()
+  if err != nil {
+    return nil, err
+  }
+
+  changes := []SchemaChange{}
+  for _, declaration := range declarations {
+    existing, err := m.app.FindCollectionByNameOrId(declaration.collection.Name)
+    if errors.Is(err, sql.ErrNoRows) {
+      changes = append(changes, createCollectionChange(declaration))
+      continue
+    }
+    if err != nil {
+      return nil, fmt.Errorf("failed to load collection %q of module %q: %w", declaration.collection.Name, declaration.module, err)
+    }
+
+    applied, skipped := reconcileCollection(declaration, existing, true)
+    changes = append(changes, applied...)
+    changes = append(changes, skipped...)
+  }
+
+  return changes, nil
+}
+
+// SyncSchema creates and updates the collections declared by the modules in a single transaction. New collections,
+// fields and indexes are always applied, changes to existing ones only if allowDestructive is true.
+//
+// The fields of existing collections are matched by name. System fields and the auth options of auth collections
+// are not compared.
+func (m *ModuleRegistry) SyncSchema(allowDestructive bool) (SchemaSyncResult, error) {
+  declarations, err := m.collectionDeclarations()
+  if err != nil {
+    return SchemaSyncResult{}, err
+  }
+
+  result := SchemaSyncResult{Applied: []SchemaChange{}, Skipped: []SchemaChange{}}
+  err = m.app.RunInTransaction(
+    func(txApp core.App) error {
+      for _, declaration := range declarations {
+        existing, err := txApp.FindCollectionByNameOrId(declaration.collection.Name)
+        if errors.Is(err, sql.ErrNoRows) {
+          if err := txApp.Save(declaration.collection); err != nil {
+            return fmt.Errorf("failed to create collection %q of module %q: %w", declaration.collection.Name, declaration.module, err)
+          }
+          result.Applied = append(result.Applied, createCollectionChange(declaration))
+          continue
+        }
+        if err != nil {
+          return fmt.Errorf("failed to load collection %q of module %q: %w", declaration.collection.Name, declaration.module, err)
+        }
+
+        applied, skipped := reconcileCollection(declaration, existing, allowDestructive)
+        if len(applied) > 0 {
+          if err := txApp.Save(existing); err != nil {
+            return fmt.Errorf("failed to update collection %q of module %q: %w", declaration.collection.Name, declaration.module, err)
+          }
+        }
+        result.Applied = append(result.Applied, applied...)
+        result.Skipped = append(result.Skipped, skipped...)
+      }
+      return nil
+    },
+  )
+  if err != nil {
+    return SchemaSyncResult{}, err
+  }
+
+  return result, nil
+}
+
+// syncSchema applies the schema changes at bootstrap and logs the changes which have been applied or skipped.
+func (m *ModuleRegistry) syncSchema() error {
+  result, err := m.SyncSchema(m.destructiveSchemaSync)
+  if err != nil {
+    return err
+  }
+
+  for _, change := range result.Applied {
+    m.app.Logger().Info("Applied a schema change", "module", change.Module, "change", change.String())
+  }
+  for _, change := range result.Skipped {
+    m.app.Logger().Warn("Skipped a destructive schema change", "module", change.Module, "change", change.String())
+  }
+
+  return nil
+}
+
+// collectionDeclarations returns the declared collections in dependency order and rejects collections which are
+// declared more than once.
+func (m *ModuleRegistry) collectionDeclarations() ([]collectionDeclaration, error) {
+  declarations := []collectionDeclaration{}
+  owners := map[string]string{}
+
+  err := m.forEachModule(
+    func(node *moduleNode) error {
+      moduleWithCollections, ok := node.module.(ModuleWithCollections)
+      if !ok {
+        return nil
+      }
+
+      for _, collection := range moduleWithCollections.Collections() {
+        if collection == nil || collection.Name == "" {
+          return fmt.Errorf("module %q declares a collection without name", node.path)
+        }
+
+        if owner, ok := owners[strings.ToLower(collection.Name)]; ok {
+          return fmt.Errorf("collection %q is declared by module %q and module %q", collection.Name, owner, node.path)
+        }
+        owners[strings.ToLower(collection.Name)] = node.path
+
+        declarations = append(declarations, collectionDeclaration{module: node.path, collection: collection})
+      }
+      return nil
+    },
+  )
+  if err != nil {
+    return nil, err
+  }
+
+  return declarations, nil
+}
+
+func createCollectionChange(declaration collectionDeclaration) SchemaChange {
+  return SchemaChange{
+    Module:     declaration.module,
+    Collection: declaration.collection.Name,
+    Kind:       SchemaChangeCreateCollection,
+  }
+}
+
+// reconcileCollection compares a declared collection with the existing one and applies the changes to existing.
+// Destructive changes are only applied if allowDestructive is true, the others are returned as skipped.
+func reconcileCollection(declaration collectionDeclaration, existing *core.Collection, allowDestructive bool) (applied []SchemaChange, skipped []SchemaChange) {
+  declared := declaration.collection
+
+  record := func(kind SchemaChangeKind, target string, destructive bool, apply func()) {
+    change := SchemaChange{
+      Module:      declaration.module,
+      Collection:  declared.Name,
+      Kind:        kind,
+      Target:      target,
+      Destructive: destructive,
+    }
+
+    if (destructive && !allowDestructive) || apply == nil {
+      skipped = append(skipped, change)
+      return
+    }
+
+    apply()
+    applied = append(applied, change)
+  }
+
+  // the fields and indexes of a collection of another type cannot be compared, it has to be migrated by hand
+  if existing.Type != declared.Type {
+    record(SchemaChangeCollectionType, "", true, nil)
+    return applied, skipped
+  }
+
+  if existing.IsView() {
+    if normalizeSQL(existing.ViewQuery) != normalizeSQL(declared.ViewQuery) {
+      record(
+        SchemaChangeUpdateViewQuery, "", true, func() {
+          existing.ViewQuery = declared.ViewQuery
+        },
+      )
+    }
+  } else {
+    reconcileFields(existing, declared, record)
+    reconcileIndexes(existing, declared, record)
+  }
+
+  rules := []struct {
+    name     string
+    existing **string
+    declared *string
+  }{
+    {"listRule", &existing.ListRule, declared.ListRule},
+    {"viewRule", &existing.ViewRule, declared.ViewRule},
+    {"createRule", &existing.CreateRule, declared.CreateRule},
+    {"updateRule", &existing.UpdateRule, declared.UpdateRule},
+    {"deleteRule", &existing.DeleteRule, declared.DeleteRule},
+  }
+  for _, rule := range rules {
+    if !equalRules(*rule.existing, rule.declared) {
+      record(
+        SchemaChangeUpdateRule, rule.name, true, func() {
+          *rule.existing = rule.declared
+        },
+      )
+    }
+  }
+
+  return applied, skipped
+}
+
+func reconcileFields(existing *core.Collection, declared *core.Collection, record func(kind SchemaChangeKind, target string, destructive bool, apply func())) {
+  for _, field := range declared.Fields {
+    if field.GetSystem() {
+      continue
+    }
+    // the ids are set on a copy, the declared collections returned by Collections are left as they are
+    field := cloneField(field)
+
+    current := existing.Fields.GetByName(field.GetName())
+    switch {
+    case current == nil:
+      record(
+        SchemaChangeAddField, field.GetName(), false, func() {
+          existing.Fields.Add(field)
+        },
+      )
+    case current.GetSystem():
+      continue
+    case current.Type() != field.Type():
+      // pocketbase rejects type changes of a field, so the field is removed and added again under a new id, which
+      // drops its column and all of its values
+      record(
+        SchemaChangeFieldType, field.GetName(), true, func() {
+          existing.Fields.RemoveByName(field.GetName())
+          field.SetId(field.Type() + core.GenerateDefaultRandomId())
+          existing.Fields.Add(field)
+        },
+      )
+    default:
+      field.SetId(current.GetId())
+      if !equalJSON(current, field) {
+        record(
+          SchemaChangeUpdateField, field.GetName(), true, func() {
+            existing.Fields.Add(field)
+          },
+        )
+      }
+    }
+  }
+
+  for _, field := range slices.Clone(existing.Fields) {
+    if !field.GetSystem() && declared.Fields.GetByName(field.GetName()) == nil {
+      record(
+        SchemaChangeRemoveField, field.GetName(), true, func() {
+          existing.Fields.RemoveByName(field.GetName())
+        },
+      )
+    }
+  }
+}
+
+// cloneField returns a shallow copy of field.
+func cloneField(field core.Field) core.Field {
+  clone := reflect.New(reflect.TypeOf(field).Elem())
+  clone.Elem().Set(reflect.ValueOf(field).Elem())
+
+  return clone.Interface().(core.Field)
+}
+
+func reconcileIndexes(existing *core.Collection, declared *core.Collection, record func(kind SchemaChangeKind, target string, destructive bool, apply func())) {
+  existingIndexes := map[string]string{}
+  for _, index := range existing.Indexes {
+    existingIndexes[strings.ToLower(dbutils.ParseIndex(index).IndexName)] = index
+  }
+
+  declaredIndexes := map[string]bool{}
+  for _, index := range declared.Indexes {
+    name := dbutils.ParseIndex(index).IndexName
+    declaredIndexes[strings.ToLower(name)] = true
+
+    current, ok := existingIndexes[strings.ToLower(name)]
+    switch {
+    case !ok:
+      record(
+        SchemaChangeAddIndex, name, false, func() {
+          existing.Indexes = append(existing.Indexes, index)
+        },
+      )
+    case normalizeSQL(current) != normalizeSQL(index):
+      record(
+        SchemaChangeUpdateIndex, name, true, func() {
+          existing.RemoveIndex(name)
+          existing.Indexes = append(existing.Indexes, index)
+        },
+      )
+    }
+  }
+
+  for _, index := range slices.Clone(existing.Indexes) {
+    name := dbutils.ParseIndex(index).IndexName
+    if !declaredIndexes[strings.ToLower(name)] {
+      record(
+        SchemaChangeRemoveIndex, name, true, func() {
+          existing.RemoveIndex(name)
+        },
+      )
+    }
+  }
+}
+
+func equalRules(a *string, b *string) bool {
+  if a == nil || b == nil {
+    return a == nil && b == nil
+  }
+
+  return *a == *b
+}
+
+func equalJSON(a any, b any) bool {
+  aData, aErr := json.Marshal(a)
+  bData, bErr := json.Marshal(b)
+
+  return aErr == nil && bErr == nil && bytes.Equal(aData, bData)
+}
+
+// normalizeSQL collapses whitespace, so formatting differences of indexes and view queries are ignored.
+func normalizeSQL(sql string) string {
+  return strings.Join(strings.Fields(sql), " ")
+}
+
+func (m *ModuleRegistry) serveSchemaDiff(e *core.RequestEvent) error {
+  changes, err := m.SchemaDiff()
+  if err != nil {
+    return err
+  }
+
+  return e.JSON(http.StatusOK, changes)
+}
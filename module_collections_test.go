@@ -0,0 +1,223 @@
+package pocketframework
+
+import (
+  "encoding/json"
+  "reflect"
+  "testing"
+
+  "github.com/pocketbase/pocketbase/core"
+  "github.com/pocketbase/pocketbase/tools/types"
+)
+
+// collectionsModule is a test module which declares the given collections.
+type collectionsModule struct {
+  testModule
+  collections []*core.Collection
+}
+
+func (c *collectionsModule) Collections() []*core.Collection {
+  return c.collections
+}
+
+func notesCollection() *core.Collection {
+  collection := core.NewBaseCollection("notes")
+  collection.Fields.Add(&core.TextField{Name: "title"}, &core.NumberField{Name: "count"})
+  collection.AddIndex("idx_notes_title", false, "title", "")
+
+  return collection
+}
+
+// membersCollection is an auth collection with a field of its own.
+func membersCollection() *core.Collection {
+  collection := core.NewAuthCollection("members")
+  collection.Fields.Add(&core.TextField{Name: "nickname"})
+
+  return collection
+}
+
+// noteTitlesCollection is a view of the notes collection.
+func noteTitlesCollection() *core.Collection {
+  collection := core.NewViewCollection("note_titles")
+  collection.ViewQuery = "SELECT id, title FROM notes"
+
+  return collection
+}
+
+func TestSyncSchema(t *testing.T) {
+  tests := []struct {
+    name string
+    // collection returns the synced collection, it defaults to notesCollection
+    collection func() *core.Collection
+    declare    func(collection *core.Collection)
+    changes    []SchemaChange
+  }{
+    {
+      name:    "unchanged",
+      declare: func(collection *core.Collection) {},
+      changes: []SchemaChange{},
+    },
+    {
+      name: "new field and index",
+      declare: func(collection *core.Collection) {
+        collection.Fields.Add(&core.TextField{Name: "body"})
+        collection.AddIndex("idx_notes_body", false, "body", "")
+      },
+      changes: []SchemaChange{
+        {Kind: SchemaChangeAddField, Target: "body"},
+        {Kind: SchemaChangeAddIndex, Target: "idx_notes_body"},
+      },
+    },
+    {
+      name: "field options",
+      declare: func(collection *core.Collection) {
+        collection.Fields.GetByName("title").(*core.TextField).Max = 10
+      },
+      changes: []SchemaChange{{Kind: SchemaChangeUpdateField, Target: "title", Destructive: true}},
+    },
+    {
+      name: "field type",
+      declare: func(collection *core.Collection) {
+        collection.Fields.RemoveByName("count")
+        collection.Fields.Add(&core.TextField{Name: "count"})
+      },
+      changes: []SchemaChange{{Kind: SchemaChangeFieldType, Target: "count", Destructive: true}},
+    },
+    {
+      name: "removed field",
+      declare: func(collection *core.Collection) {
+        collection.Fields.RemoveByName("count")
+      },
+      changes: []SchemaChange{{Kind: SchemaChangeRemoveField, Target: "count", Destructive: true}},
+    },
+    {
+      name: "changed index",
+      declare: func(collection *core.Collection) {
+        collection.RemoveIndex("idx_notes_title")
+        collection.AddIndex("idx_notes_title", true, "title", "")
+      },
+      changes: []SchemaChange{{Kind: SchemaChangeUpdateIndex, Target: "idx_notes_title", Destructive: true}},
+    },
+    {
+      name: "removed index",
+      declare: func(collection *core.Collection) {
+        collection.RemoveIndex("idx_notes_title")
+      },
+      changes: []SchemaChange{{Kind: SchemaChangeRemoveIndex, Target: "idx_notes_title", Destructive: true}},
+    },
+    {
+      name: "rule",
+      declare: func(collection *core.Collection) {
+        collection.ListRule = types.Pointer("")
+      },
+      changes: []SchemaChange{{Kind: SchemaChangeUpdateRule, Target: "listRule", Destructive: true}},
+    },
+    {
+      name:       "auth collection unchanged",
+      collection: membersCollection,
+      declare:    func(collection *core.Collection) {},
+      changes:    []SchemaChange{},
+    },
+    {
+      name:       "auth collection fields",
+      collection: membersCollection,
+      declare: func(collection *core.Collection) {
+        collection.Fields.RemoveByName("nickname")
+        collection.Fields.Add(&core.TextField{Name: "bio"})
+      },
+      changes: []SchemaChange{
+        {Kind: SchemaChangeAddField, Target: "bio"},
+        {Kind: SchemaChangeRemoveField, Target: "nickname", Destructive: true},
+      },
+    },
+    {
+      name:       "view unchanged",
+      collection: noteTitlesCollection,
+      declare: func(collection *core.Collection) {
+        collection.ViewQuery = "SELECT id,  title\n  FROM notes"
+      },
+      changes: []SchemaChange{},
+    },
+    {
+      name:       "view query",
+      collection: noteTitlesCollection,
+      declare: func(collection *core.Collection) {
+        collection.ViewQuery = "SELECT id, count FROM notes"
+      },
+      changes: []SchemaChange{{Kind: SchemaChangeUpdateViewQuery, Destructive: true}},
+    },
+  }
+
+  for _, tt := range tests {
+    t.Run(
+      tt.name, func(t *testing.T) {
+        collection := tt.collection
+        if collection == nil {
+          collection = notesCollection
+        }
+
+        collections := []*core.Collection{notesCollection()}
+        if tt.collection != nil {
+          collections = append(collections, collection())
+        }
+        module := &collectionsModule{testModule: testModule{name: "notes"}, collections: collections}
+        registry, app := newTestRegistry(t, module)
+
+        declared := collection()
+        tt.declare(declared)
+        module.collections = append(collections[:len(collections)-1:len(collections)-1], declared)
+        original, err := json.Marshal(declared)
+        if err != nil {
+          t.Fatal(err)
+        }
+
+        for i := range tt.changes {
+          tt.changes[i].Module = "notes"
+          tt.changes[i].Collection = declared.Name
+        }
+
+        changes, err := registry.SchemaDiff()
+        if err != nil {
+          t.Fatalf("SchemaDiff() failed: %v", err)
+        }
+        if !reflect.DeepEqual(changes, tt.changes) {
+          t.Fatalf("SchemaDiff() = %v, want %v", changes, tt.changes)
+        }
+
+        result, err := registry.SyncSchema(false)
+        if err != nil {
+          t.Fatalf("SyncSchema(false) failed: %v", err)
+        }
+        for _, change := range result.Applied {
+          if change.Destructive {
+            t.Errorf("SyncSchema(false) applied the destructive change %s", change)
+          }
+        }
+        if len(result.Applied)+len(result.Skipped) != len(tt.changes) {
+          t.Errorf("SyncSchema(false) = %+v, want the changes %v", result, tt.changes)
+        }
+
+        if _, err := registry.SyncSchema(true); err != nil {
+          t.Fatalf("SyncSchema(true) failed: %v", err)
+        }
+        if changes, err := registry.SchemaDiff(); err != nil || len(changes) > 0 {
+          t.Errorf("SchemaDiff() after the sync = %v, %v", changes, err)
+        }
+
+        // the sync must not change the declared collection, e.g. by setting the ids of its fields
+        if synced, _ := json.Marshal(declared); string(synced) != string(original) {
+          t.Errorf("the declared collection has been changed from %s to %s", original, synced)
+        }
+
+        existing, err := app.FindCollectionByNameOrId(declared.Name)
+        if err != nil {
+          t.Fatalf("failed to load the collection: %v", err)
+        }
+        for _, field := range declared.Fields {
+          if current := existing.Fields.GetByName(field.GetName()); current == nil || current.Type() != field.Type() {
+            t.Errorf("field %q has not been synced", field.GetName())
+          }
+        }
+      },
+    )
+  }
+}
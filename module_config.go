@@ -0,0 +1,356 @@
+package pocketframework
+
+import (
+  "encoding"
+  "encoding/json"
+  "errors"
+  "fmt"
+  "os"
+  "reflect"
+  "sort"
+  "strconv"
+  "strings"
+  "time"
+  "unicode"
+
+  validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// ModuleConfig can be embedded into a module to give it a typed config which is loaded by the registry.
+//
+// Values are resolved in the following order, later sources overriding earlier ones:
+//   - the "default" struct tag
+//   - the module's entry in the config file (see WithConfigFile)
+//   - environment variables named after the module path and the field, e.g. BILLING_INVOICES_TRIAL_DAYS
+//
+// The env variable name of a field can be changed with the "env" struct tag. If the config implements
+// validation.Validatable, it is validated after loading.
+type ModuleConfig[T any] struct {
+  config T
+}
+
+// ConfigTarget implements ModuleWithConfig.
+func (c *ModuleConfig[T]) ConfigTarget() any {
+  return &c.config
+}
+
+// Config returns the loaded config.
+func (c *ModuleConfig[T]) Config() T {
+  return c.config
+}
+
+// ConfigFieldError describes a single invalid config field.
+type ConfigFieldError struct {
+  Module string `json:"module"`
+  Field  string `json:"field"`
+  Error  string `json:"error"`
+}
+
+// ConfigError lists every invalid config field across all modules.
+type ConfigError struct {
+  Fields []ConfigFieldError
+}
+
+func (e *ConfigError) Error() string {
+  lines := make([]string, 0, len(e.Fields))
+  for _, field := range e.Fields {
+    lines = append(lines, fmt.Sprintf("%s: %s: %s", field.Module, field.Field, field.Error))
+  }
+
+  return "invalid module config:\n  " + strings.Join(lines, "\n  ")
+}
+
+// WithConfigFile loads module configs from a JSON file, keyed by module path. A missing file is ignored.
+func WithConfigFile(path string) RegistryOption {
+  return func(m *ModuleRegistry) {
+    m.configFile = path
+  }
+}
+
+// WithEnvPrefix prepends a prefix to the env variables of all module configs, e.g. "APP" reads APP_BILLING_TRIAL_DAYS.
+func WithEnvPrefix(prefix string) RegistryOption {
+  return func(m *ModuleRegistry) {
+    m.envPrefix = prefix
+  }
+}
+
+// loadConfigs populates and validates the configs of all modules and reports every invalid field at once.
+func (m *ModuleRegistry) loadConfigs() error {
+  fileConfigs := map[string]json.RawMessage{}
+  if m.configFile != "" {
+    data, err := os.ReadFile(m.configFile)
+    if err != nil && !errors.Is(err, os.ErrNotExist) {
+      return fmt.Errorf("failed to read config file %q: %w", m.configFile, err)
+    }
+
+    if len(data) > 0 {
+      if err := json.Unmarshal(data, &fileConfigs); err != nil {
+        return fmt.Errorf("failed to parse config file %q: %w", m.configFile, err)
+      }
+    }
+  }
+
+  configErr := &ConfigError{}
+  for _, root := range m.roots {
+    _ = root.walk(
+      func(node *moduleNode) error {
+        moduleWithConfig, ok := node.module.(ModuleWithConfig)
+        if !ok {
+          return nil
+        }
+
+        for _, fieldErr := range loadConfig(moduleWithConfig.ConfigTarget(), fileConfigs[node.path], m.moduleEnvPrefix(node)) {
+          fieldErr.Module = node.path
+          configErr.Fields = append(configErr.Fields, fieldErr)
+        }
+        return nil
+      },
+    )
+  }
+
+  if len(configErr.Fields) > 0 {
+    return configErr
+  }
+
+  return nil
+}
+
+// moduleEnvPrefix returns the env variable prefix of a module, e.g. "APP_BILLING_INVOICES_".
+func (m *ModuleRegistry) moduleEnvPrefix(node *moduleNode) string {
+  prefix := envName(strings.NewReplacer("/", "_", "-", "_", ".", "_").Replace(node.path)) + "_"
+  if m.envPrefix != "" {
+    prefix = strings.TrimSuffix(m.envPrefix, "_") + "_" + prefix
+  }
+
+  return prefix
+}
+
+func loadConfig(target any, fileConfig json.RawMessage, envPrefix string) []ConfigFieldError {
+  value := reflect.ValueOf(target)
+  if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+    return []ConfigFieldError{{Field: "-", Error: fmt.Sprintf("config target must be a pointer to a struct, got %T", target)}}
+  }
+
+  errs := applyDefaults(value.Elem(), "")
+
+  if len(fileConfig) > 0 {
+    if err := json.Unmarshal(fileConfig, target); err != nil {
+      errs = append(errs, ConfigFieldError{Field: "-", Error: "invalid config file entry: " + err.Error()})
+    }
+  }
+
+  errs = append(errs, applyEnv(value.Elem(), "", envPrefix)...)
+
+  // the config is validated even if it could not be loaded completely, so every invalid field is reported at once.
+  // Fields which failed to load are only reported once.
+  if validatable, ok := target.(validation.Validatable); ok {
+    failed := map[string]bool{}
+    for _, fieldErr := range errs {
+      failed[fieldErr.Field] = true
+    }
+
+    for _, fieldErr := range validationFieldErrors("", validatable.Validate()) {
+      if !failed[fieldErr.Field] {
+        errs = append(errs, fieldErr)
+      }
+    }
+  }
+
+  return errs
+}
+
+func applyDefaults(value reflect.Value, path string) []ConfigFieldError {
+  errs := []ConfigFieldError{}
+
+  forEachConfigField(
+    value, path, func(field reflect.StructField, fieldValue reflect.Value, fieldPath string) bool {
+      if raw, ok := field.Tag.Lookup("default"); ok {
+        if err := setValueFromString(fieldValue, raw); err != nil {
+          errs = append(errs, ConfigFieldError{Field: fieldPath, Error: "invalid default: " + err.Error()})
+        }
+        return false
+      }
+      return true
+    },
+  )
+
+  return errs
+}
+
+func applyEnv(value reflect.Value, path string, prefix string) []ConfigFieldError {
+  errs := []ConfigFieldError{}
+
+  var apply func(value reflect.Value, path string, prefix string)
+  apply = func(value reflect.Value, path string, prefix string) {
+    forEachConfigField(
+      value, path, func(field reflect.StructField, fieldValue reflect.Value, fieldPath string) bool {
+        name := field.Tag.Get("env")
+        if name == "" {
+          name = envName(field.Name)
+        }
+
+        if isNestedConfig(fieldValue) {
+          apply(fieldValue, fieldPath, prefix+name+"_")
+          return false
+        }
+
+        if raw, ok := os.LookupEnv(prefix + name); ok {
+          if err := setValueFromString(fieldValue, raw); err != nil {
+            errs = append(errs, ConfigFieldError{Field: fieldPath, Error: fmt.Sprintf("invalid value of %s: %s", prefix+name, err)})
+          }
+        }
+        return false
+      },
+    )
+  }
+  apply(value, path, prefix)
+
+  return errs
+}
+
+// forEachConfigField calls fn for all exported fields. Nested structs are visited as long as fn returns true.
+func forEachConfigField(value reflect.Value, path string, fn func(field reflect.StructField, fieldValue reflect.Value, fieldPath string) bool) {
+  for i := 0; i < value.NumField(); i++ {
+    field := value.Type().Field(i)
+    if !field.IsExported() || field.Tag.Get("env") == "-" {
+      continue
+    }
+
+    fieldPath := configFieldName(field)
+    if path != "" {
+      fieldPath = path + "." + fieldPath
+    }
+
+    fieldValue := value.Field(i)
+    if fn(field, fieldValue, fieldPath) && isNestedConfig(fieldValue) {
+      forEachConfigField(fieldValue, fieldPath, fn)
+    }
+  }
+}
+
+func isNestedConfig(value reflect.Value) bool {
+  if value.Kind() != reflect.Struct {
+    return false
+  }
+
+  _, ok := value.Addr().Interface().(encoding.TextUnmarshaler)
+  return !ok
+}
+
+func setValueFromString(value reflect.Value, raw string) error {
+  if unmarshaler, ok := value.Addr().Interface().(encoding.TextUnmarshaler); ok {
+    return unmarshaler.UnmarshalText([]byte(raw))
+  }
+
+  if value.Type() == reflect.TypeOf(time.Duration(0)) {
+    duration, err := time.ParseDuration(raw)
+    if err != nil {
+      return err
+    }
+    value.SetInt(int64(duration))
+    return nil
+  }
+
+  switch value.Kind() {
+  case reflect.String:
+    value.SetString(raw)
+  case reflect.Bool:
+    parsed, err := strconv.ParseBool(raw)
+    if err != nil {
+      return err
+    }
+    value.SetBool(parsed)
+  case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+    parsed, err := strconv.ParseInt(raw, 10, value.Type().Bits())
+    if err != nil {
+      return err
+    }
+    value.SetInt(parsed)
+  case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+    parsed, err := strconv.ParseUint(raw, 10, value.Type().Bits())
+    if err != nil {
+      return err
+    }
+    value.SetUint(parsed)
+  case reflect.Float32, reflect.Float64:
+    parsed, err := strconv.ParseFloat(raw, value.Type().Bits())
+    if err != nil {
+      return err
+    }
+    value.SetFloat(parsed)
+  case reflect.Slice:
+    parts := []string{}
+    for _, part := range strings.Split(raw, ",") {
+      if part = strings.TrimSpace(part); part != "" {
+        parts = append(parts, part)
+      }
+    }
+
+    slice := reflect.MakeSlice(value.Type(), len(parts), len(parts))
+    for i, part := range parts {
+      if err := setValueFromString(slice.Index(i), part); err != nil {
+        return err
+      }
+    }
+    value.Set(slice)
+  default:
+    return fmt.Errorf("unsupported config type %s", value.Type())
+  }
+
+  return nil
+}
+
+func validationFieldErrors(path string, err error) []ConfigFieldError {
+  if err == nil {
+    return nil
+  }
+
+  var validationErrs validation.Errors
+  if !errors.As(err, &validationErrs) {
+    field := path
+    if field == "" {
+      field = "-"
+    }
+    return []ConfigFieldError{{Field: field, Error: err.Error()}}
+  }
+
+  keys := make([]string, 0, len(validationErrs))
+  for key := range validationErrs {
+    keys = append(keys, key)
+  }
+  sort.Strings(keys)
+
+  result := []ConfigFieldError{}
+  for _, key := range keys {
+    fieldPath := key
+    if path != "" {
+      fieldPath = path + "." + key
+    }
+    result = append(result, validationFieldErrors(fieldPath, validationErrs[key])...)
+  }
+
+  return result
+}
+
+func configFieldName(field reflect.StructField) string {
+  if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name != "" && name != "-" {
+    return name
+  }
+
+  return field.Name
+}
+
+// envName converts a name such as "TrialDays" or "billing_invoices" to "TRIAL_DAYS" or "BILLING_INVOICES".
+func envName(name string) string {
+  var builder strings.Builder
+
+  runes := []rune(name)
+  for i, r := range runes {
+    if i > 0 && unicode.IsUpper(r) && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) && runes[i-1] != '_' {
+      builder.WriteRune('_')
+    }
+    builder.WriteRune(unicode.ToUpper(r))
+  }
+
+  return builder.String()
+}
@@ -0,0 +1,199 @@
+package pocketframework
+
+import (
+  "encoding/json"
+  "errors"
+  "reflect"
+  "testing"
+  "time"
+
+  validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type testConfig struct {
+  TrialDays int           `json:"trialDays" default:"14"`
+  Currency  string        `json:"currency" default:"EUR"`
+  Timeout   time.Duration `json:"timeout" default:"5s"`
+  Regions   []string      `json:"regions"`
+  Enabled   bool          `json:"enabled"`
+  Mail      struct {
+    Sender string `json:"sender" default:"noreply@example.com"`
+  } `json:"mail"`
+}
+
+func (c *testConfig) Validate() error {
+  return validation.ValidateStruct(c, validation.Field(&c.TrialDays, validation.Max(30)))
+}
+
+func TestLoadConfig(t *testing.T) {
+  tests := []struct {
+    name   string
+    file   string
+    env    map[string]string
+    expect func(config *testConfig)
+    errs   []ConfigFieldError
+  }{
+    {
+      name:   "defaults",
+      expect: func(config *testConfig) {},
+    },
+    {
+      name: "file overrides defaults",
+      file: `{"trialDays": 7, "mail": {"sender": "billing@example.com"}}`,
+      expect: func(config *testConfig) {
+        config.TrialDays = 7
+        config.Mail.Sender = "billing@example.com"
+      },
+    },
+    {
+      name: "env overrides file",
+      file: `{"trialDays": 7}`,
+      env: map[string]string{
+        "BILLING_TRIAL_DAYS":  "21",
+        "BILLING_REGIONS":     "eu, us",
+        "BILLING_MAIL_SENDER": "env@example.com",
+      },
+      expect: func(config *testConfig) {
+        config.TrialDays = 21
+        config.Regions = []string{"eu", "us"}
+        config.Mail.Sender = "env@example.com"
+      },
+    },
+    {
+      name: "invalid env value",
+      env:  map[string]string{"BILLING_TIMEOUT": "soon"},
+      errs: []ConfigFieldError{{Field: "timeout", Error: `invalid value of BILLING_TIMEOUT: time: invalid duration "soon"`}},
+    },
+    {
+      name: "validation",
+      file: `{"trialDays": 90}`,
+      errs: []ConfigFieldError{{Field: "trialDays", Error: "must be no greater than 30"}},
+    },
+    {
+      name: "load and validation errors",
+      file: `{"trialDays": 90}`,
+      env:  map[string]string{"BILLING_TIMEOUT": "soon"},
+      errs: []ConfigFieldError{
+        {Field: "timeout", Error: `invalid value of BILLING_TIMEOUT: time: invalid duration "soon"`},
+        {Field: "trialDays", Error: "must be no greater than 30"},
+      },
+    },
+    {
+      name: "field which failed to load",
+      env:  map[string]string{"BILLING_TRIAL_DAYS": "90 days"},
+      errs: []ConfigFieldError{{Field: "trialDays", Error: `invalid value of BILLING_TRIAL_DAYS: strconv.ParseInt: parsing "90 days": invalid syntax`}},
+    },
+  }
+
+  for _, test := range tests {
+    t.Run(
+      test.name, func(t *testing.T) {
+        for name, value := range test.env {
+          t.Setenv(name, value)
+        }
+
+        config := &testConfig{}
+        errs := loadConfig(config, json.RawMessage(test.file), "BILLING_")
+
+        if test.errs != nil {
+          if !reflect.DeepEqual(errs, test.errs) {
+            t.Fatalf("expected errors %v, got %v", test.errs, errs)
+          }
+          return
+        }
+        if len(errs) > 0 {
+          t.Fatalf("unexpected errors: %v", errs)
+        }
+
+        expected := &testConfig{TrialDays: 14, Currency: "EUR", Timeout: 5 * time.Second}
+        expected.Mail.Sender = "noreply@example.com"
+        test.expect(expected)
+
+        if !reflect.DeepEqual(config, expected) {
+          t.Fatalf("expected %+v, got %+v", expected, config)
+        }
+      },
+    )
+  }
+}
+
+// configModule is a test module with a config.
+type configModule struct {
+  testModule
+  config testConfig
+}
+
+func (c *configModule) ConfigTarget() any {
+  return &c.config
+}
+
+func TestLoadConfigs(t *testing.T) {
+  t.Setenv("BILLING_TIMEOUT", "soon")
+  t.Setenv("CRM_TRIAL_DAYS", "90")
+
+  m := NewModuleRegistry(nil, "/api")
+  m.Register(&configModule{testModule: testModule{name: "billing"}})
+  m.Register(&configModule{testModule: testModule{name: "crm"}})
+
+  var err error
+  if m.roots, m.ordered, err = buildModuleTree(m.modules); err != nil {
+    t.Fatal(err)
+  }
+
+  var configErr *ConfigError
+  if err := m.loadConfigs(); !errors.As(err, &configErr) {
+    t.Fatalf("expected a *ConfigError, got %v", err)
+  }
+
+  expected := []ConfigFieldError{
+    {Module: "billing", Field: "timeout", Error: `invalid value of BILLING_TIMEOUT: time: invalid duration "soon"`},
+    {Module: "crm", Field: "trialDays", Error: "must be no greater than 30"},
+  }
+  if !reflect.DeepEqual(configErr.Fields, expected) {
+    t.Fatalf("expected errors %v, got %v", expected, configErr.Fields)
+  }
+}
+
+func TestConfigEnabledField(t *testing.T) {
+  t.Setenv("BILLING_ENABLED", "false")
+  t.Setenv("CRM_MODULE_ENABLED", "false")
+
+  billing := &configModule{testModule: testModule{name: "billing"}}
+  m := NewModuleRegistry(nil, "/api")
+  m.Register(billing)
+  m.Register(&configModule{testModule: testModule{name: "crm"}})
+
+  var err error
+  if m.roots, m.ordered, err = buildModuleTree(m.modules); err != nil {
+    t.Fatal(err)
+  }
+  if err := errors.Join(m.loadConfigs(), m.loadFlagEnv()); err != nil {
+    t.Fatalf("unexpected error: %v", err)
+  }
+
+  // BILLING_ENABLED sets the config field, the flag is read from BILLING_MODULE_ENABLED
+  if billing.config.Enabled {
+    t.Errorf("expected the config field Enabled to be false")
+  }
+  if !m.Enabled("billing") {
+    t.Errorf("expected module %q to be enabled", "billing")
+  }
+  if m.Enabled("crm") {
+    t.Errorf("expected module %q to be disabled", "crm")
+  }
+}
+
+func TestEnvName(t *testing.T) {
+  tests := map[string]string{
+    "TrialDays":        "TRIAL_DAYS",
+    "APIKey":           "API_KEY",
+    "billing_invoices": "BILLING_INVOICES",
+    "MaxUploadMB":      "MAX_UPLOAD_MB",
+  }
+
+  for name, expected := range tests {
+    if actual := envName(name); actual != expected {
+      t.Errorf("envName(%q) = %q, expected %q", name, actual, expected)
+    }
+  }
+}
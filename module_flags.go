@@ -0,0 +1,307 @@
+package pocketframework
+
+import (
+  "database/sql"
+  "errors"
+  "fmt"
+  "net/http"
+  "os"
+  "strconv"
+
+  "github.com/pocketbase/pocketbase/core"
+  "github.com/pocketbase/pocketbase/tools/hook"
+  "github.com/pocketbase/pocketbase/tools/router"
+)
+
+// FlagsCollection is the system collection used to persist the module flags which have been changed at runtime.
+const FlagsCollection = "_pocketframework_module_flags"
+
+// FlagSource is the source of the current value of a module flag.
+type FlagSource string
+
+const (
+  FlagSourceDefault  FlagSource = "default"
+  FlagSourceEnv      FlagSource = "env"
+  FlagSourceDatabase FlagSource = "database"
+)
+
+// moduleFlagPriority makes the flag check run before any auth middleware, so disabled modules look the same to
+// every caller.
+const moduleFlagPriority = MiddlewarePriorityBeforeAuth - 1
+
+// ModuleFlag reports whether a module is enabled.
+type ModuleFlag struct {
+  Module string `json:"module"`
+
+  // Enabled is the flag of the module itself.
+  Enabled bool       `json:"enabled"`
+  Source  FlagSource `json:"source"`
+
+  // Effective is false if the module or one of its parents is disabled.
+  Effective bool `json:"effective"`
+}
+
+type moduleFlag struct {
+  env      *bool
+  database *bool
+}
+
+// WithDisabledModuleStatus sets the status sent for requests to disabled modules. Defaults to 404 Not Found, use
+// http.StatusServiceUnavailable to make the outage visible to clients.
+func WithDisabledModuleStatus(status int) RegistryOption {
+  return func(m *ModuleRegistry) {
+    m.disabledStatus = status
+  }
+}
+
+// Enabled reports whether the module with the given path and all of its parents are enabled.
+//
+// A module is enabled unless it has been disabled with SetEnabled or the env variable named after the module path,
+// e.g. BILLING_INVOICES_MODULE_ENABLED=false. The routes of disabled modules respond with 404 Not Found and their hook
+// handlers are skipped. They are still migrated and started, so they can be re-enabled at any time.
+func (m *ModuleRegistry) Enabled(path string) bool {
+  m.mu.RLock()
+  defer m.mu.RUnlock()
+
+  node := m.findNode(path)
+  if node == nil {
+    return false
+  }
+
+  return m.effectiveFlag(node)
+}
+
+func (m *ModuleRegistry) nodeEnabled(node *moduleNode) bool {
+  m.mu.RLock()
+  defer m.mu.RUnlock()
+
+  return m.effectiveFlag(node)
+}
+
+// Flags returns the flags of all modules.
+func (m *ModuleRegistry) Flags() []ModuleFlag {
+  m.mu.RLock()
+  defer m.mu.RUnlock()
+
+  flags := []ModuleFlag{}
+  for _, root := range m.roots {
+    _ = root.walk(
+      func(node *moduleNode) error {
+        enabled, source := m.ownFlag(node)
+        flags = append(
+          flags, ModuleFlag{
+            Module:    node.path,
+            Enabled:   enabled,
+            Source:    source,
+            Effective: m.effectiveFlag(node),
+          },
+        )
+        return nil
+      },
+    )
+  }
+
+  return flags
+}
+
+// SetEnabled enables or disables the module with the given path and persists the change. It takes effect immediately
+// and overrides the env variable of the module.
+func (m *ModuleRegistry) SetEnabled(path string, enabled bool) error {
+  if m.findNode(path) == nil {
+    return fmt.Errorf("unknown module %q", path)
+  }
+
+  record, err := m.app.FindFirstRecordByData(FlagsCollection, "module", path)
+  if errors.Is(err, sql.ErrNoRows) {
+    collection, err := m.app.FindCachedCollectionByNameOrId(FlagsCollection)
+    if err != nil {
+      return fmt.Errorf("failed to load the module flags collection: %w", err)
+    }
+    record = core.NewRecord(collection)
+    record.Set("module", path)
+  } else if err != nil {
+    return fmt.Errorf("failed to load the flag of module %q: %w", path, err)
+  }
+
+  record.Set("enabled", enabled)
+  if err := m.app.Save(record); err != nil {
+    return fmt.Errorf("failed to persist the flag of module %q: %w", path, err)
+  }
+
+  m.mu.Lock()
+  m.flags[path] = moduleFlag{env: m.flags[path].env, database: &enabled}
+  m.mu.Unlock()
+
+  return nil
+}
+
+// ResetEnabled removes the persisted flag of the module with the given path, so its env variable or the default
+// applies again.
+func (m *ModuleRegistry) ResetEnabled(path string) error {
+  if m.findNode(path) == nil {
+    return fmt.Errorf("unknown module %q", path)
+  }
+
+  record, err := m.app.FindFirstRecordByData(FlagsCollection, "module", path)
+  if err != nil && !errors.Is(err, sql.ErrNoRows) {
+    return fmt.Errorf("failed to load the flag of module %q: %w", path, err)
+  }
+  if record != nil {
+    if err := m.app.Delete(record); err != nil {
+      return fmt.Errorf("failed to reset the flag of module %q: %w", path, err)
+    }
+  }
+
+  m.mu.Lock()
+  m.flags[path] = moduleFlag{env: m.flags[path].env}
+  m.mu.Unlock()
+
+  return nil
+}
+
+// ReloadFlags reloads the persisted flags from the database, e.g. after they have been changed by another instance.
+// Changes to the flags collection made through the dashboard or the records API are reloaded automatically.
+func (m *ModuleRegistry) ReloadFlags() error {
+  records, err := m.app.FindAllRecords(FlagsCollection)
+  if err != nil {
+    return fmt.Errorf("failed to load the module flags: %w", err)
+  }
+
+  persisted := make(map[string]bool, len(records))
+  for _, record := range records {
+    persisted[record.GetString("module")] = record.GetBool("enabled")
+  }
+
+  m.mu.Lock()
+  defer m.mu.Unlock()
+
+  for path, flag := range m.flags {
+    flag.database = nil
+    if enabled, ok := persisted[path]; ok {
+      flag.database = &enabled
+    }
+    m.flags[path] = flag
+  }
+
+  return nil
+}
+
+// bindFlagReload reloads the flags whenever a record of the flags collection changes.
+func (m *ModuleRegistry) bindFlagReload() {
+  reload := func(e *core.RecordEvent) error {
+    if err := e.Next(); err != nil {
+      return err
+    }
+
+    if err := m.ReloadFlags(); err != nil {
+      e.App.Logger().Warn("Failed to reload the module flags", "error", err)
+    }
+    return nil
+  }
+
+  m.app.OnRecordAfterCreateSuccess(FlagsCollection).BindFunc(reload)
+  m.app.OnRecordAfterUpdateSuccess(FlagsCollection).BindFunc(reload)
+  m.app.OnRecordAfterDeleteSuccess(FlagsCollection).BindFunc(reload)
+}
+
+// loadFlagEnv reads the env variables of all module flags.
+func (m *ModuleRegistry) loadFlagEnv() error {
+  m.flags = map[string]moduleFlag{}
+
+  for _, root := range m.roots {
+    err := root.walk(
+      func(node *moduleNode) error {
+        flag := moduleFlag{}
+
+        // the flag is namespaced, so it does not collide with a config field named Enabled
+        name := m.moduleEnvPrefix(node) + "MODULE_ENABLED"
+        if raw, ok := os.LookupEnv(name); ok {
+          enabled, err := strconv.ParseBool(raw)
+          if err != nil {
+            return fmt.Errorf("invalid value of %s: %w", name, err)
+          }
+          flag.env = &enabled
+        }
+
+        m.flags[node.path] = flag
+        return nil
+      },
+    )
+    if err != nil {
+      return err
+    }
+  }
+
+  return nil
+}
+
+// ownFlag returns the flag of the node itself. The caller must hold the lock.
+func (m *ModuleRegistry) ownFlag(node *moduleNode) (bool, FlagSource) {
+  flag := m.flags[node.path]
+
+  switch {
+  case flag.database != nil:
+    return *flag.database, FlagSourceDatabase
+  case flag.env != nil:
+    return *flag.env, FlagSourceEnv
+  default:
+    return true, FlagSourceDefault
+  }
+}
+
+// effectiveFlag reports whether the node and all of its parents are enabled. The caller must hold the lock.
+func (m *ModuleRegistry) effectiveFlag(node *moduleNode) bool {
+  for ; node != nil; node = node.parent {
+    if enabled, _ := m.ownFlag(node); !enabled {
+      return false
+    }
+  }
+
+  return true
+}
+
+// moduleFlagMiddleware rejects the requests to the routes of the given module while it is disabled.
+func (m *ModuleRegistry) moduleFlagMiddleware(node *moduleNode) *hook.Handler[*core.RequestEvent] {
+  return &hook.Handler[*core.RequestEvent]{
+    Priority: moduleFlagPriority,
+    Func: func(e *core.RequestEvent) error {
+      if m.nodeEnabled(node) {
+        return e.Next()
+      }
+
+      if m.disabledStatus == http.StatusNotFound {
+        return e.NotFoundError("", nil)
+      }
+      return router.NewApiError(m.disabledStatus, http.StatusText(m.disabledStatus)+".", nil)
+    },
+  }
+}
+
+type setFlagRequest struct {
+  Module  string `json:"-" path:"module"`
+  Enabled bool   `json:"enabled"`
+}
+
+type flagRequest struct {
+  Module string `json:"-" path:"module"`
+}
+
+func (m *ModuleRegistry) serveFlags(e *core.RequestEvent) error {
+  return e.JSON(http.StatusOK, m.Flags())
+}
+
+func (m *ModuleRegistry) serveSetFlag(e *core.RequestEvent, req setFlagRequest) (NoContent, error) {
+  if m.findNode(req.Module) == nil {
+    return NoContent{}, e.NotFoundError("Unknown module.", nil)
+  }
+
+  return NoContent{}, m.SetEnabled(req.Module, req.Enabled)
+}
+
+func (m *ModuleRegistry) serveResetFlag(e *core.RequestEvent, req flagRequest) (NoContent, error) {
+  if m.findNode(req.Module) == nil {
+    return NoContent{}, e.NotFoundError("Unknown module.", nil)
+  }
+
+  return NoContent{}, m.ResetEnabled(req.Module)
+}
@@ -0,0 +1,72 @@
+package pocketframework
+
+import (
+  "testing"
+)
+
+func TestModuleFlags(t *testing.T) {
+  registry, app := newTestRegistry(
+    t,
+    &testModule{name: "billing", children: []Module{&testModule{name: "invoices"}}},
+    &testModule{name: "shop"},
+  )
+
+  collection, err := app.FindCollectionByNameOrId(FlagsCollection)
+  if err != nil {
+    t.Fatalf("the flags collection has not been created: %v", err)
+  }
+  if !collection.System {
+    t.Errorf("the flags collection is not a system collection")
+  }
+
+  if err := registry.SetEnabled("billing", false); err != nil {
+    t.Fatalf("SetEnabled() failed: %v", err)
+  }
+
+  tests := []struct {
+    module  string
+    enabled bool
+  }{
+    {module: "billing", enabled: false},
+    {module: "billing/invoices", enabled: false},
+    {module: "shop", enabled: true},
+    {module: "unknown", enabled: false},
+  }
+
+  for _, tt := range tests {
+    if enabled := registry.Enabled(tt.module); enabled != tt.enabled {
+      t.Errorf("Enabled(%q) = %v, want %v", tt.module, enabled, tt.enabled)
+    }
+  }
+
+  record, err := app.FindFirstRecordByData(FlagsCollection, "module", "billing")
+  if err != nil {
+    t.Fatalf("the flag has not been persisted: %v", err)
+  }
+
+  // changes made through the records API apply without an explicit reload
+  record.Set("enabled", true)
+  if err := app.Save(record); err != nil {
+    t.Fatalf("failed to update the flag record: %v", err)
+  }
+  if !registry.Enabled("billing/invoices") {
+    t.Errorf("Enabled(%q) = false after the flag record has been updated", "billing/invoices")
+  }
+
+  if err := registry.SetEnabled("shop", false); err != nil {
+    t.Fatalf("SetEnabled() failed: %v", err)
+  }
+  if err := registry.ResetEnabled("shop"); err != nil {
+    t.Fatalf("ResetEnabled() failed: %v", err)
+  }
+  if !registry.Enabled("shop") {
+    t.Errorf("Enabled(%q) = false after the flag has been reset", "shop")
+  }
+  if _, err := app.FindFirstRecordByData(FlagsCollection, "module", "shop"); err == nil {
+    t.Errorf("the flag record of %q has not been deleted", "shop")
+  }
+
+  if err := registry.SetEnabled("unknown", false); err == nil {
+    t.Errorf("SetEnabled() of an unknown module succeeded")
+  }
+}
@@ -0,0 +1,361 @@
+package pocketframework
+
+import (
+  "github.com/pocketbase/pocketbase/core"
+)
+
+// ScopedHooks implements ModuleAppHooks on top of the hooks of a pocketbase app and records every handler bound
+// through it, so they can be listed and removed again. The registry hands a ScopedHooks to every module.
+type ScopedHooks struct {
+  app   AppHooks
+  scope *hookScope
+}
+
+// NewScopedHooks creates a new ScopedHooks for the given hooks, usually a core.App.
+func NewScopedHooks(app AppHooks) *ScopedHooks {
+  return newScopedHooks(app, &hookScope{})
+}
+
+func newScopedHooks(app AppHooks, scope *hookScope) *ScopedHooks {
+  return &ScopedHooks{app: app, scope: scope}
+}
+
+// Bindings returns the handlers bound through these hooks, in the order they were bound.
+func (h *ScopedHooks) Bindings() []HookBinding {
+  return h.scope.list()
+}
+
+// UnbindAll removes every handler bound through these hooks. Handlers bound by other modules are left untouched.
+func (h *ScopedHooks) UnbindAll() {
+  h.scope.unbindAll()
+}
+
+func (h *ScopedHooks) OnBootstrap() *Hook[*core.BootstrapEvent] {
+  return newHook(h.scope, "OnBootstrap", h.app.OnBootstrap())
+}
+
+func (h *ScopedHooks) OnTerminate() *Hook[*core.TerminateEvent] {
+  return newHook(h.scope, "OnTerminate", h.app.OnTerminate())
+}
+
+func (h *ScopedHooks) OnBackupCreate() *Hook[*core.BackupEvent] {
+  return newHook(h.scope, "OnBackupCreate", h.app.OnBackupCreate())
+}
+
+func (h *ScopedHooks) OnBackupRestore() *Hook[*core.BackupEvent] {
+  return newHook(h.scope, "OnBackupRestore", h.app.OnBackupRestore())
+}
+
+func (h *ScopedHooks) OnModelValidate(tags ...string) *TaggedHook[*core.ModelEvent] {
+  return newTaggedHook(h.scope, "OnModelValidate", h.app.OnModelValidate(tags...), tags)
+}
+
+func (h *ScopedHooks) OnModelCreate(tags ...string) *TaggedHook[*core.ModelEvent] {
+  return newTaggedHook(h.scope, "OnModelCreate", h.app.OnModelCreate(tags...), tags)
+}
+
+func (h *ScopedHooks) OnModelCreateExecute(tags ...string) *TaggedHook[*core.ModelEvent] {
+  return newTaggedHook(h.scope, "OnModelCreateExecute", h.app.OnModelCreateExecute(tags...), tags)
+}
+
+func (h *ScopedHooks) OnModelAfterCreateSuccess(tags ...string) *TaggedHook[*core.ModelEvent] {
+  return newTaggedHook(h.scope, "OnModelAfterCreateSuccess", h.app.OnModelAfterCreateSuccess(tags...), tags)
+}
+
+func (h *ScopedHooks) OnModelAfterCreateError(tags ...string) *TaggedHook[*core.ModelErrorEvent] {
+  return newTaggedHook(h.scope, "OnModelAfterCreateError", h.app.OnModelAfterCreateError(tags...), tags)
+}
+
+func (h *ScopedHooks) OnModelUpdate(tags ...string) *TaggedHook[*core.ModelEvent] {
+  return newTaggedHook(h.scope, "OnModelUpdate", h.app.OnModelUpdate(tags...), tags)
+}
+
+func (h *ScopedHooks) OnModelUpdateExecute(tags ...string) *TaggedHook[*core.ModelEvent] {
+  return newTaggedHook(h.scope, "OnModelUpdateExecute", h.app.OnModelUpdateExecute(tags...), tags)
+}
+
+func (h *ScopedHooks) OnModelAfterUpdateSuccess(tags ...string) *TaggedHook[*core.ModelEvent] {
+  return newTaggedHook(h.scope, "OnModelAfterUpdateSuccess", h.app.OnModelAfterUpdateSuccess(tags...), tags)
+}
+
+func (h *ScopedHooks) OnModelAfterUpdateError(tags ...string) *TaggedHook[*core.ModelErrorEvent] {
+  return newTaggedHook(h.scope, "OnModelAfterUpdateError", h.app.OnModelAfterUpdateError(tags...), tags)
+}
+
+func (h *ScopedHooks) OnModelDelete(tags ...string) *TaggedHook[*core.ModelEvent] {
+  return newTaggedHook(h.scope, "OnModelDelete", h.app.OnModelDelete(tags...), tags)
+}
+
+func (h *ScopedHooks) OnModelDeleteExecute(tags ...string) *TaggedHook[*core.ModelEvent] {
+  return newTaggedHook(h.scope, "OnModelDeleteExecute", h.app.OnModelDeleteExecute(tags...), tags)
+}
+
+func (h *ScopedHooks) OnModelAfterDeleteSuccess(tags ...string) *TaggedHook[*core.ModelEvent] {
+  return newTaggedHook(h.scope, "OnModelAfterDeleteSuccess", h.app.OnModelAfterDeleteSuccess(tags...), tags)
+}
+
+func (h *ScopedHooks) OnModelAfterDeleteError(tags ...string) *TaggedHook[*core.ModelErrorEvent] {
+  return newTaggedHook(h.scope, "OnModelAfterDeleteError", h.app.OnModelAfterDeleteError(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordEnrich(tags ...string) *TaggedHook[*core.RecordEnrichEvent] {
+  return newTaggedHook(h.scope, "OnRecordEnrich", h.app.OnRecordEnrich(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordValidate(tags ...string) *TaggedHook[*core.RecordEvent] {
+  return newTaggedHook(h.scope, "OnRecordValidate", h.app.OnRecordValidate(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordCreate(tags ...string) *TaggedHook[*core.RecordEvent] {
+  return newTaggedHook(h.scope, "OnRecordCreate", h.app.OnRecordCreate(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordCreateExecute(tags ...string) *TaggedHook[*core.RecordEvent] {
+  return newTaggedHook(h.scope, "OnRecordCreateExecute", h.app.OnRecordCreateExecute(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordAfterCreateSuccess(tags ...string) *TaggedHook[*core.RecordEvent] {
+  return newTaggedHook(h.scope, "OnRecordAfterCreateSuccess", h.app.OnRecordAfterCreateSuccess(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordAfterCreateError(tags ...string) *TaggedHook[*core.RecordErrorEvent] {
+  return newTaggedHook(h.scope, "OnRecordAfterCreateError", h.app.OnRecordAfterCreateError(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordUpdate(tags ...string) *TaggedHook[*core.RecordEvent] {
+  return newTaggedHook(h.scope, "OnRecordUpdate", h.app.OnRecordUpdate(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordUpdateExecute(tags ...string) *TaggedHook[*core.RecordEvent] {
+  return newTaggedHook(h.scope, "OnRecordUpdateExecute", h.app.OnRecordUpdateExecute(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordAfterUpdateSuccess(tags ...string) *TaggedHook[*core.RecordEvent] {
+  return newTaggedHook(h.scope, "OnRecordAfterUpdateSuccess", h.app.OnRecordAfterUpdateSuccess(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordAfterUpdateError(tags ...string) *TaggedHook[*core.RecordErrorEvent] {
+  return newTaggedHook(h.scope, "OnRecordAfterUpdateError", h.app.OnRecordAfterUpdateError(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordDelete(tags ...string) *TaggedHook[*core.RecordEvent] {
+  return newTaggedHook(h.scope, "OnRecordDelete", h.app.OnRecordDelete(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordDeleteExecute(tags ...string) *TaggedHook[*core.RecordEvent] {
+  return newTaggedHook(h.scope, "OnRecordDeleteExecute", h.app.OnRecordDeleteExecute(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordAfterDeleteSuccess(tags ...string) *TaggedHook[*core.RecordEvent] {
+  return newTaggedHook(h.scope, "OnRecordAfterDeleteSuccess", h.app.OnRecordAfterDeleteSuccess(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordAfterDeleteError(tags ...string) *TaggedHook[*core.RecordErrorEvent] {
+  return newTaggedHook(h.scope, "OnRecordAfterDeleteError", h.app.OnRecordAfterDeleteError(tags...), tags)
+}
+
+func (h *ScopedHooks) OnCollectionValidate(tags ...string) *TaggedHook[*core.CollectionEvent] {
+  return newTaggedHook(h.scope, "OnCollectionValidate", h.app.OnCollectionValidate(tags...), tags)
+}
+
+func (h *ScopedHooks) OnCollectionCreate(tags ...string) *TaggedHook[*core.CollectionEvent] {
+  return newTaggedHook(h.scope, "OnCollectionCreate", h.app.OnCollectionCreate(tags...), tags)
+}
+
+func (h *ScopedHooks) OnCollectionCreateExecute(tags ...string) *TaggedHook[*core.CollectionEvent] {
+  return newTaggedHook(h.scope, "OnCollectionCreateExecute", h.app.OnCollectionCreateExecute(tags...), tags)
+}
+
+func (h *ScopedHooks) OnCollectionAfterCreateSuccess(tags ...string) *TaggedHook[*core.CollectionEvent] {
+  return newTaggedHook(h.scope, "OnCollectionAfterCreateSuccess", h.app.OnCollectionAfterCreateSuccess(tags...), tags)
+}
+
+func (h *ScopedHooks) OnCollectionAfterCreateError(tags ...string) *TaggedHook[*core.CollectionErrorEvent] {
+  return newTaggedHook(h.scope, "OnCollectionAfterCreateError", h.app.OnCollectionAfterCreateError(tags...), tags)
+}
+
+func (h *ScopedHooks) OnCollectionUpdate(tags ...string) *TaggedHook[*core.CollectionEvent] {
+  return newTaggedHook(h.scope, "OnCollectionUpdate", h.app.OnCollectionUpdate(tags...), tags)
+}
+
+func (h *ScopedHooks) OnCollectionUpdateExecute(tags ...string) *TaggedHook[*core.CollectionEvent] {
+  return newTaggedHook(h.scope, "OnCollectionUpdateExecute", h.app.OnCollectionUpdateExecute(tags...), tags)
+}
+
+func (h *ScopedHooks) OnCollectionAfterUpdateSuccess(tags ...string) *TaggedHook[*core.CollectionEvent] {
+  return newTaggedHook(h.scope, "OnCollectionAfterUpdateSuccess", h.app.OnCollectionAfterUpdateSuccess(tags...), tags)
+}
+
+func (h *ScopedHooks) OnCollectionAfterUpdateError(tags ...string) *TaggedHook[*core.CollectionErrorEvent] {
+  return newTaggedHook(h.scope, "OnCollectionAfterUpdateError", h.app.OnCollectionAfterUpdateError(tags...), tags)
+}
+
+func (h *ScopedHooks) OnCollectionDelete(tags ...string) *TaggedHook[*core.CollectionEvent] {
+  return newTaggedHook(h.scope, "OnCollectionDelete", h.app.OnCollectionDelete(tags...), tags)
+}
+
+func (h *ScopedHooks) OnCollectionDeleteExecute(tags ...string) *TaggedHook[*core.CollectionEvent] {
+  return newTaggedHook(h.scope, "OnCollectionDeleteExecute", h.app.OnCollectionDeleteExecute(tags...), tags)
+}
+
+func (h *ScopedHooks) OnCollectionAfterDeleteSuccess(tags ...string) *TaggedHook[*core.CollectionEvent] {
+  return newTaggedHook(h.scope, "OnCollectionAfterDeleteSuccess", h.app.OnCollectionAfterDeleteSuccess(tags...), tags)
+}
+
+func (h *ScopedHooks) OnCollectionAfterDeleteError(tags ...string) *TaggedHook[*core.CollectionErrorEvent] {
+  return newTaggedHook(h.scope, "OnCollectionAfterDeleteError", h.app.OnCollectionAfterDeleteError(tags...), tags)
+}
+
+func (h *ScopedHooks) OnMailerSend() *Hook[*core.MailerEvent] {
+  return newHook(h.scope, "OnMailerSend", h.app.OnMailerSend())
+}
+
+func (h *ScopedHooks) OnMailerRecordAuthAlertSend(tags ...string) *TaggedHook[*core.MailerRecordEvent] {
+  return newTaggedHook(h.scope, "OnMailerRecordAuthAlertSend", h.app.OnMailerRecordAuthAlertSend(tags...), tags)
+}
+
+func (h *ScopedHooks) OnMailerRecordPasswordResetSend(tags ...string) *TaggedHook[*core.MailerRecordEvent] {
+  return newTaggedHook(h.scope, "OnMailerRecordPasswordResetSend", h.app.OnMailerRecordPasswordResetSend(tags...), tags)
+}
+
+func (h *ScopedHooks) OnMailerRecordVerificationSend(tags ...string) *TaggedHook[*core.MailerRecordEvent] {
+  return newTaggedHook(h.scope, "OnMailerRecordVerificationSend", h.app.OnMailerRecordVerificationSend(tags...), tags)
+}
+
+func (h *ScopedHooks) OnMailerRecordEmailChangeSend(tags ...string) *TaggedHook[*core.MailerRecordEvent] {
+  return newTaggedHook(h.scope, "OnMailerRecordEmailChangeSend", h.app.OnMailerRecordEmailChangeSend(tags...), tags)
+}
+
+func (h *ScopedHooks) OnMailerRecordOTPSend(tags ...string) *TaggedHook[*core.MailerRecordEvent] {
+  return newTaggedHook(h.scope, "OnMailerRecordOTPSend", h.app.OnMailerRecordOTPSend(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRealtimeConnectRequest() *Hook[*core.RealtimeConnectRequestEvent] {
+  return newHook(h.scope, "OnRealtimeConnectRequest", h.app.OnRealtimeConnectRequest())
+}
+
+func (h *ScopedHooks) OnRealtimeMessageSend() *Hook[*core.RealtimeMessageEvent] {
+  return newHook(h.scope, "OnRealtimeMessageSend", h.app.OnRealtimeMessageSend())
+}
+
+func (h *ScopedHooks) OnRealtimeSubscribeRequest() *Hook[*core.RealtimeSubscribeRequestEvent] {
+  return newHook(h.scope, "OnRealtimeSubscribeRequest", h.app.OnRealtimeSubscribeRequest())
+}
+
+func (h *ScopedHooks) OnSettingsListRequest() *Hook[*core.SettingsListRequestEvent] {
+  return newHook(h.scope, "OnSettingsListRequest", h.app.OnSettingsListRequest())
+}
+
+func (h *ScopedHooks) OnSettingsUpdateRequest() *Hook[*core.SettingsUpdateRequestEvent] {
+  return newHook(h.scope, "OnSettingsUpdateRequest", h.app.OnSettingsUpdateRequest())
+}
+
+func (h *ScopedHooks) OnSettingsReload() *Hook[*core.SettingsReloadEvent] {
+  return newHook(h.scope, "OnSettingsReload", h.app.OnSettingsReload())
+}
+
+func (h *ScopedHooks) OnFileDownloadRequest(tags ...string) *TaggedHook[*core.FileDownloadRequestEvent] {
+  return newTaggedHook(h.scope, "OnFileDownloadRequest", h.app.OnFileDownloadRequest(tags...), tags)
+}
+
+func (h *ScopedHooks) OnFileTokenRequest(tags ...string) *TaggedHook[*core.FileTokenRequestEvent] {
+  return newTaggedHook(h.scope, "OnFileTokenRequest", h.app.OnFileTokenRequest(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordAuthRequest(tags ...string) *TaggedHook[*core.RecordAuthRequestEvent] {
+  return newTaggedHook(h.scope, "OnRecordAuthRequest", h.app.OnRecordAuthRequest(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordAuthWithPasswordRequest(tags ...string) *TaggedHook[*core.RecordAuthWithPasswordRequestEvent] {
+  return newTaggedHook(h.scope, "OnRecordAuthWithPasswordRequest", h.app.OnRecordAuthWithPasswordRequest(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordAuthWithOAuth2Request(tags ...string) *TaggedHook[*core.RecordAuthWithOAuth2RequestEvent] {
+  return newTaggedHook(h.scope, "OnRecordAuthWithOAuth2Request", h.app.OnRecordAuthWithOAuth2Request(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordAuthRefreshRequest(tags ...string) *TaggedHook[*core.RecordAuthRefreshRequestEvent] {
+  return newTaggedHook(h.scope, "OnRecordAuthRefreshRequest", h.app.OnRecordAuthRefreshRequest(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordRequestPasswordResetRequest(tags ...string) *TaggedHook[*core.RecordRequestPasswordResetRequestEvent] {
+  return newTaggedHook(h.scope, "OnRecordRequestPasswordResetRequest", h.app.OnRecordRequestPasswordResetRequest(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordConfirmPasswordResetRequest(tags ...string) *TaggedHook[*core.RecordConfirmPasswordResetRequestEvent] {
+  return newTaggedHook(h.scope, "OnRecordConfirmPasswordResetRequest", h.app.OnRecordConfirmPasswordResetRequest(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordRequestVerificationRequest(tags ...string) *TaggedHook[*core.RecordRequestVerificationRequestEvent] {
+  return newTaggedHook(h.scope, "OnRecordRequestVerificationRequest", h.app.OnRecordRequestVerificationRequest(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordConfirmVerificationRequest(tags ...string) *TaggedHook[*core.RecordConfirmVerificationRequestEvent] {
+  return newTaggedHook(h.scope, "OnRecordConfirmVerificationRequest", h.app.OnRecordConfirmVerificationRequest(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordRequestEmailChangeRequest(tags ...string) *TaggedHook[*core.RecordRequestEmailChangeRequestEvent] {
+  return newTaggedHook(h.scope, "OnRecordRequestEmailChangeRequest", h.app.OnRecordRequestEmailChangeRequest(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordConfirmEmailChangeRequest(tags ...string) *TaggedHook[*core.RecordConfirmEmailChangeRequestEvent] {
+  return newTaggedHook(h.scope, "OnRecordConfirmEmailChangeRequest", h.app.OnRecordConfirmEmailChangeRequest(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordRequestOTPRequest(tags ...string) *TaggedHook[*core.RecordCreateOTPRequestEvent] {
+  return newTaggedHook(h.scope, "OnRecordRequestOTPRequest", h.app.OnRecordRequestOTPRequest(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordAuthWithOTPRequest(tags ...string) *TaggedHook[*core.RecordAuthWithOTPRequestEvent] {
+  return newTaggedHook(h.scope, "OnRecordAuthWithOTPRequest", h.app.OnRecordAuthWithOTPRequest(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordsListRequest(tags ...string) *TaggedHook[*core.RecordsListRequestEvent] {
+  return newTaggedHook(h.scope, "OnRecordsListRequest", h.app.OnRecordsListRequest(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordViewRequest(tags ...string) *TaggedHook[*core.RecordRequestEvent] {
+  return newTaggedHook(h.scope, "OnRecordViewRequest", h.app.OnRecordViewRequest(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordCreateRequest(tags ...string) *TaggedHook[*core.RecordRequestEvent] {
+  return newTaggedHook(h.scope, "OnRecordCreateRequest", h.app.OnRecordCreateRequest(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordUpdateRequest(tags ...string) *TaggedHook[*core.RecordRequestEvent] {
+  return newTaggedHook(h.scope, "OnRecordUpdateRequest", h.app.OnRecordUpdateRequest(tags...), tags)
+}
+
+func (h *ScopedHooks) OnRecordDeleteRequest(tags ...string) *TaggedHook[*core.RecordRequestEvent] {
+  return newTaggedHook(h.scope, "OnRecordDeleteRequest", h.app.OnRecordDeleteRequest(tags...), tags)
+}
+
+func (h *ScopedHooks) OnCollectionsListRequest() *Hook[*core.CollectionsListRequestEvent] {
+  return newHook(h.scope, "OnCollectionsListRequest", h.app.OnCollectionsListRequest())
+}
+
+func (h *ScopedHooks) OnCollectionViewRequest() *Hook[*core.CollectionRequestEvent] {
+  return newHook(h.scope, "OnCollectionViewRequest", h.app.OnCollectionViewRequest())
+}
+
+func (h *ScopedHooks) OnCollectionCreateRequest() *Hook[*core.CollectionRequestEvent] {
+  return newHook(h.scope, "OnCollectionCreateRequest", h.app.OnCollectionCreateRequest())
+}
+
+func (h *ScopedHooks) OnCollectionUpdateRequest() *Hook[*core.CollectionRequestEvent] {
+  return newHook(h.scope, "OnCollectionUpdateRequest", h.app.OnCollectionUpdateRequest())
+}
+
+func (h *ScopedHooks) OnCollectionDeleteRequest() *Hook[*core.CollectionRequestEvent] {
+  return newHook(h.scope, "OnCollectionDeleteRequest", h.app.OnCollectionDeleteRequest())
+}
+
+func (h *ScopedHooks) OnCollectionsImportRequest() *Hook[*core.CollectionsImportRequestEvent] {
+  return newHook(h.scope, "OnCollectionsImportRequest", h.app.OnCollectionsImportRequest())
+}
+
+func (h *ScopedHooks) OnBatchRequest() *Hook[*core.BatchRequestEvent] {
+  return newHook(h.scope, "OnBatchRequest", h.app.OnBatchRequest())
+}
+
+var _ ModuleAppHooks = (*ScopedHooks)(nil)
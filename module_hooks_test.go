@@ -0,0 +1,185 @@
+package pocketframework
+
+import (
+  "reflect"
+  "strings"
+  "testing"
+
+  "github.com/pocketbase/pocketbase/core"
+  "github.com/pocketbase/pocketbase/tools/hook"
+)
+
+func TestScopedHooks(t *testing.T) {
+  app := core.NewBaseApp(core.BaseAppConfig{DataDir: t.TempDir()})
+  hooks := NewScopedHooks(app)
+
+  calls := []string{}
+  record := func(name string) func(e *core.RecordEvent) error {
+    return func(e *core.RecordEvent) error {
+      calls = append(calls, name)
+      return e.Next()
+    }
+  }
+  trigger := func(collection string) {
+    calls = []string{}
+    event := &core.RecordEvent{}
+    event.Record = core.NewRecord(core.NewBaseCollection(collection))
+    if err := app.OnRecordCreate().Trigger(event); err != nil {
+      t.Fatalf("failed to trigger the hook: %v", err)
+    }
+  }
+
+  notesId := hooks.OnRecordCreate("notes").BindFunc(record("notes"))
+  hooks.OnRecordCreate().Bind(&hook.Handler[*core.RecordEvent]{Id: "all", Func: record("all")})
+  hooks.OnBootstrap().BindFunc(
+    func(e *core.BootstrapEvent) error {
+      return e.Next()
+    },
+  )
+
+  bindings := hooks.Bindings()
+  expected := []HookBinding{
+    {Hook: "OnRecordCreate", Tags: []string{"notes"}, Id: notesId},
+    {Hook: "OnRecordCreate", Id: "all"},
+    {Hook: "OnBootstrap", Id: bindings[2].Id},
+  }
+  if !reflect.DeepEqual(bindings, expected) {
+    t.Fatalf("expected the bindings %+v, got %+v", expected, bindings)
+  }
+
+  trigger("notes")
+  if strings.Join(calls, ",") != "notes,all" {
+    t.Fatalf("expected both handlers to run for notes, got %v", calls)
+  }
+  trigger("tasks")
+  if strings.Join(calls, ",") != "all" {
+    t.Fatalf("expected only the untagged handler to run for tasks, got %v", calls)
+  }
+
+  t.Run(
+    "rebinding an id replaces the handler", func(t *testing.T) {
+      hooks.OnRecordCreate().Bind(&hook.Handler[*core.RecordEvent]{Id: "all", Func: record("all v2")})
+
+      if len(hooks.Bindings()) != 3 {
+        t.Fatalf("expected the binding to be replaced, got %+v", hooks.Bindings())
+      }
+      trigger("tasks")
+      if strings.Join(calls, ",") != "all v2" {
+        t.Fatalf("expected the new handler to run, got %v", calls)
+      }
+    },
+  )
+
+  t.Run(
+    "unbind", func(t *testing.T) {
+      hooks.OnRecordCreate("notes").Unbind(notesId)
+
+      for _, binding := range hooks.Bindings() {
+        if binding.Id == notesId {
+          t.Fatalf("expected the binding to be removed, got %+v", hooks.Bindings())
+        }
+      }
+      trigger("notes")
+      if strings.Join(calls, ",") != "all v2" {
+        t.Fatalf("expected the unbound handler not to run, got %v", calls)
+      }
+    },
+  )
+
+  t.Run(
+    "unbind all", func(t *testing.T) {
+      other := NewScopedHooks(app)
+      other.OnRecordCreate().BindFunc(record("other"))
+
+      hooks.UnbindAll()
+
+      if len(hooks.Bindings()) != 0 {
+        t.Fatalf("expected no bindings, got %+v", hooks.Bindings())
+      }
+      trigger("notes")
+      if strings.Join(calls, ",") != "other" {
+        t.Fatalf("expected only the handler of the other scope to run, got %v", calls)
+      }
+    },
+  )
+
+  t.Run(
+    "unwrap", func(t *testing.T) {
+      if hooks.OnBootstrap().Unwrap() != app.OnBootstrap() {
+        t.Fatal("expected the hook of the app")
+      }
+      if !hooks.OnRecordCreate("notes").Unwrap().CanTriggerOn([]string{"notes"}) {
+        t.Fatal("expected the tags to be kept")
+      }
+    },
+  )
+}
+
+// hookBindingModule binds a handler for the records of the notes collection.
+type hookBindingModule struct {
+  testModule
+  calls *[]string
+}
+
+func (h *hookBindingModule) RegisterHooks(app ModuleAppHooks) error {
+  app.OnRecordCreate("notes").BindFunc(
+    func(e *core.RecordEvent) error {
+      *h.calls = append(*h.calls, h.name)
+      return e.Next()
+    },
+  )
+  return nil
+}
+
+func TestModuleHooks(t *testing.T) {
+  calls := []string{}
+  registry, app := newTestRegistry(
+    t,
+    &hookBindingModule{testModule: testModule{name: "billing"}, calls: &calls},
+    &hookBindingModule{testModule: testModule{name: "crm"}, calls: &calls},
+  )
+
+  trigger := func() {
+    calls = []string{}
+    event := &core.RecordEvent{}
+    event.Record = core.NewRecord(core.NewBaseCollection("notes"))
+    if err := app.OnRecordCreate().Trigger(event); err != nil {
+      t.Fatalf("failed to trigger the hook: %v", err)
+    }
+  }
+
+  trigger()
+  if strings.Join(calls, ",") != "billing,crm" {
+    t.Fatalf("expected the handlers of both modules to run, got %v", calls)
+  }
+
+  bindings, err := registry.Hooks("billing")
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(bindings) != 1 || bindings[0].Hook != "OnRecordCreate" || !reflect.DeepEqual(bindings[0].Tags, []string{"notes"}) {
+    t.Fatalf("expected the binding of the module, got %+v", bindings)
+  }
+
+  if err := registry.UnbindHooks("billing"); err != nil {
+    t.Fatal(err)
+  }
+
+  trigger()
+  if strings.Join(calls, ",") != "crm" {
+    t.Fatalf("expected only the handler of crm to run, got %v", calls)
+  }
+  if bindings, _ := registry.Hooks("billing"); len(bindings) != 0 {
+    t.Fatalf("expected no bindings, got %+v", bindings)
+  }
+  if bindings, _ := registry.Hooks("crm"); len(bindings) != 1 {
+    t.Fatalf("expected the binding of crm to be kept, got %+v", bindings)
+  }
+
+  if _, err := registry.Hooks("unknown"); err == nil || err.Error() != `unknown module "unknown"` {
+    t.Fatalf("expected an error for an unknown module, got %v", err)
+  }
+  if err := registry.UnbindHooks("unknown"); err == nil {
+    t.Fatal("expected an error for an unknown module")
+  }
+}
@@ -0,0 +1,110 @@
+package pocketframework
+
+import (
+  "fmt"
+  "net/http"
+
+  "github.com/pocketbase/pocketbase/core"
+)
+
+// ModuleMetadata describes a module for the people operating it.
+type ModuleMetadata struct {
+  // Name is the human readable name of the module, e.g. "Invoices". The identity of a module is still its Name() of
+  // ModuleWithName or its prefix.
+  Name        string `json:"name,omitempty"`
+  Version     string `json:"version,omitempty"`
+  Description string `json:"description,omitempty"`
+  Owner       string `json:"owner,omitempty"`
+}
+
+// ModuleInfo describes a registered module and its children.
+type ModuleInfo struct {
+  Path      string         `json:"path"`
+  Prefix    string         `json:"prefix"`
+  MountedAt string         `json:"mountedAt"`
+  Metadata  ModuleMetadata `json:"metadata"`
+  State     ModuleState    `json:"state"`
+  Routes    int            `json:"routes"`
+  Hooks     []HookBinding  `json:"hooks"`
+  Children  []ModuleInfo   `json:"children"`
+}
+
+// Modules returns the tree of registered modules with their routes, hooks and lifecycle state.
+func (m *ModuleRegistry) Modules() ([]ModuleInfo, error) {
+  routes, err := m.Routes()
+  if err != nil {
+    return nil, err
+  }
+
+  routeCounts := map[string]int{}
+  for _, route := range routes {
+    routeCounts[route.Module]++
+  }
+
+  m.mu.RLock()
+  defer m.mu.RUnlock()
+
+  var describe func(node *moduleNode, parentMount string) ModuleInfo
+  describe = func(node *moduleNode, parentMount string) ModuleInfo {
+    info := ModuleInfo{
+      Path:      node.path,
+      Prefix:    node.module.Prefix(),
+      MountedAt: parentMount + node.module.Prefix(),
+      State:     node.state,
+      Routes:    routeCounts[node.path],
+      Hooks:     node.hooks.list(),
+      Children:  make([]ModuleInfo, 0, len(node.children)),
+    }
+    if moduleWithMetadata, ok := node.module.(ModuleWithMetadata); ok {
+      info.Metadata = moduleWithMetadata.Metadata()
+    }
+    if info.Hooks == nil {
+      info.Hooks = []HookBinding{}
+    }
+
+    for _, child := range node.children {
+      info.Children = append(info.Children, describe(child, info.MountedAt))
+    }
+
+    return info
+  }
+
+  modules := make([]ModuleInfo, 0, len(m.roots))
+  for _, root := range m.roots {
+    modules = append(modules, describe(root, m.apiPrefix))
+  }
+
+  return modules, nil
+}
+
+func (m *ModuleRegistry) serveModules(e *core.RequestEvent) error {
+  modules, err := m.Modules()
+  if err != nil {
+    return e.InternalServerError("Failed to list the modules.", err)
+  }
+
+  return e.JSON(http.StatusOK, modules)
+}
+
+// Hooks returns the handlers the module with the given path bound through its ModuleAppHooks.
+func (m *ModuleRegistry) Hooks(path string) ([]HookBinding, error) {
+  node := m.findNode(path)
+  if node == nil {
+    return nil, fmt.Errorf("unknown module %q", path)
+  }
+
+  return node.hooks.list(), nil
+}
+
+// UnbindHooks removes every handler the module with the given path bound through its ModuleAppHooks. The handlers of
+// its children are left untouched.
+func (m *ModuleRegistry) UnbindHooks(path string) error {
+  node := m.findNode(path)
+  if node == nil {
+    return fmt.Errorf("unknown module %q", path)
+  }
+
+  node.hooks.unbindAll()
+
+  return nil
+}
@@ -0,0 +1,129 @@
+package pocketframework
+
+import (
+  "encoding/json"
+  "net/http"
+  "net/http/httptest"
+  "reflect"
+  "testing"
+
+  "github.com/pocketbase/pocketbase/core"
+)
+
+// inventoryModule is a test module with metadata and a hook.
+type inventoryModule struct {
+  testModule
+  metadata ModuleMetadata
+}
+
+func (i *inventoryModule) Metadata() ModuleMetadata {
+  return i.metadata
+}
+
+func (i *inventoryModule) RegisterHooks(app ModuleAppHooks) error {
+  app.OnRecordCreate("invoices").BindFunc(
+    func(e *core.RecordEvent) error {
+      return e.Next()
+    },
+  )
+  return nil
+}
+
+func TestInventory(t *testing.T) {
+  handler := func(e *core.RequestEvent) error {
+    return e.NoContent(http.StatusNoContent)
+  }
+
+  registry, app := newTestRegistry(
+    t, &inventoryModule{
+      testModule: testModule{
+        name:   "billing",
+        prefix: "/billing",
+        routes: func(groups RouterGroups) error {
+          groups.Public.GET("/status", handler)
+          return nil
+        },
+        children: []Module{
+          &testModule{
+            name:   "invoices",
+            prefix: "/invoices",
+            routes: func(groups RouterGroups) error {
+              groups.Public.GET("", handler)
+              groups.Admin.POST("", handler)
+              return nil
+            },
+          },
+        },
+      },
+      metadata: ModuleMetadata{Name: "Billing", Version: "1.2.0", Owner: "payments"},
+    },
+  )
+  server := serveTestRegistry(t, registry, app)
+
+  tests := []struct {
+    name   string
+    token  string
+    status int
+  }{
+    {name: "guest", status: http.StatusUnauthorized},
+    {name: "superuser", token: newTestSuperuserToken(t, app), status: http.StatusOK},
+  }
+
+  for _, test := range tests {
+    t.Run(
+      test.name, func(t *testing.T) {
+        request := httptest.NewRequest(http.MethodGet, "/api"+FrameworkRoutesPrefix+"/modules", nil)
+        if test.token != "" {
+          request.Header.Set("Authorization", test.token)
+        }
+        recorder := httptest.NewRecorder()
+        server.ServeHTTP(recorder, request)
+
+        if recorder.Code != test.status {
+          t.Fatalf("expected status %d, got %d: %s", test.status, recorder.Code, recorder.Body)
+        }
+        if test.status != http.StatusOK {
+          return
+        }
+
+        var modules []ModuleInfo
+        if err := json.Unmarshal(recorder.Body.Bytes(), &modules); err != nil {
+          t.Fatalf("failed to decode the modules: %v", err)
+        }
+        if len(modules) != 1 {
+          t.Fatalf("expected one root module, got %+v", modules)
+        }
+        billing := modules[0]
+
+        hooks := billing.Hooks
+        if len(hooks) != 1 || hooks[0].Hook != "OnRecordCreate" || !reflect.DeepEqual(hooks[0].Tags, []string{"invoices"}) || hooks[0].Id == "" {
+          t.Errorf("expected the hook of the module, got %+v", hooks)
+        }
+        billing.Hooks = nil
+
+        expected := ModuleInfo{
+          Path:      "billing",
+          Prefix:    "/billing",
+          MountedAt: "/api/billing",
+          Metadata:  ModuleMetadata{Name: "Billing", Version: "1.2.0", Owner: "payments"},
+          State:     ModuleStateStarted,
+          Routes:    1,
+          Children: []ModuleInfo{
+            {
+              Path:      "billing/invoices",
+              Prefix:    "/invoices",
+              MountedAt: "/api/billing/invoices",
+              State:     ModuleStateStarted,
+              Routes:    2,
+              Hooks:     []HookBinding{},
+              Children:  []ModuleInfo{},
+            },
+          },
+        }
+        if !reflect.DeepEqual(billing, expected) {
+          t.Errorf("expected %+v, got %+v", expected, billing)
+        }
+      },
+    )
+  }
+}
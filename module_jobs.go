@@ -0,0 +1,300 @@
+package pocketframework
+
+import (
+  "context"
+  "errors"
+  "fmt"
+  "net/http"
+  "sync"
+  "time"
+
+  "github.com/pocketbase/pocketbase/core"
+)
+
+var (
+  // ErrJobNotFound is returned when a job is triggered which does not exist.
+  ErrJobNotFound = errors.New("job not found")
+
+  // ErrJobRunning is returned when a job is triggered while it is still running.
+  ErrJobRunning = errors.New("job is already running")
+
+  // ErrJobModuleDisabled is returned when a job is triggered while its module is disabled.
+  ErrJobModuleDisabled = errors.New("module of the job is disabled")
+
+  // ErrJobsNotRunning is returned when a job is triggered before the modules have been started or while they are
+  // stopping.
+  ErrJobsNotRunning = errors.New("jobs are not running")
+)
+
+// Job is a periodic job owned by a module.
+type Job struct {
+  // Name identifies the job within its module, e.g. "cleanup-drafts".
+  Name string
+
+  // Schedule is a cron expression such as "*/5 * * * *" or a macro such as "@hourly".
+  Schedule string
+
+  // Run executes the job. ctx is cancelled when the app terminates.
+  Run func(ctx context.Context) error
+}
+
+// JobInfo describes a scheduled job and the outcome of its last run.
+type JobInfo struct {
+  Id       string `json:"id"`
+  Module   string `json:"module"`
+  Name     string `json:"name"`
+  Schedule string `json:"schedule"`
+  Running  bool   `json:"running"`
+  Runs     int    `json:"runs"`
+
+  LastRun      *time.Time    `json:"lastRun"`
+  LastDuration time.Duration `json:"lastDuration"`
+  LastError    string        `json:"lastError,omitempty"`
+}
+
+type moduleJob struct {
+  id   string
+  node *moduleNode
+  job  Job
+
+  mu           sync.Mutex
+  running      bool
+  runs         int
+  lastRun      *time.Time
+  lastDuration time.Duration
+  lastError    string
+}
+
+// Jobs returns all jobs of the registered modules.
+func (m *ModuleRegistry) Jobs() []JobInfo {
+  infos := make([]JobInfo, 0, len(m.jobs))
+  for _, job := range m.jobs {
+    infos = append(infos, job.info())
+  }
+
+  return infos
+}
+
+// TriggerJob runs the job with the given id in the background, regardless of its schedule. It fails with
+// ErrJobNotFound, ErrJobModuleDisabled, ErrJobRunning or ErrJobsNotRunning.
+func (m *ModuleRegistry) TriggerJob(id string) error {
+  for _, job := range m.jobs {
+    if job.id != id {
+      continue
+    }
+
+    if !m.nodeEnabled(job.node) {
+      return fmt.Errorf("%w: %q", ErrJobModuleDisabled, job.node.path)
+    }
+    if err := m.addJobRun(); err != nil {
+      return err
+    }
+    if !job.begin() {
+      m.jobsWg.Done()
+      return ErrJobRunning
+    }
+
+    go func() {
+      defer m.jobsWg.Done()
+      m.runJob(job)
+    }()
+
+    return nil
+  }
+
+  return fmt.Errorf("%w: %q", ErrJobNotFound, id)
+}
+
+// addJobRun adds a run to the wait group of the jobs unless they have not been started yet or are stopping. The
+// caller must call jobsWg.Done once the run has finished.
+func (m *ModuleRegistry) addJobRun() error {
+  m.jobsMu.Lock()
+  defer m.jobsMu.Unlock()
+
+  if m.jobsCtx == nil || m.jobsStopping {
+    return ErrJobsNotRunning
+  }
+  m.jobsWg.Add(1)
+
+  return nil
+}
+
+// collectJobs validates the jobs of all modules. The id of a job is its module path and name, e.g.
+// "billing/invoices:cleanup-drafts".
+func (m *ModuleRegistry) collectJobs() error {
+  m.jobs = nil
+
+  for _, root := range m.roots {
+    err := root.walk(
+      func(node *moduleNode) error {
+        moduleWithJobs, ok := node.module.(ModuleWithJobs)
+        if !ok {
+          return nil
+        }
+
+        names := map[string]bool{}
+        for _, job := range moduleWithJobs.Jobs() {
+          if job.Name == "" || job.Schedule == "" || job.Run == nil {
+            return fmt.Errorf("module %q has a job without name, schedule or run function", node.path)
+          }
+          if names[job.Name] {
+            return fmt.Errorf("module %q has multiple jobs named %q", node.path, job.Name)
+          }
+          names[job.Name] = true
+
+          m.jobs = append(m.jobs, &moduleJob{id: node.path + ":" + job.Name, node: node, job: job})
+        }
+        return nil
+      },
+    )
+    if err != nil {
+      return err
+    }
+  }
+
+  return nil
+}
+
+// scheduleJobs adds all jobs to the cron of the app. Runs are skipped while the previous run of the same job is
+// still in progress or the module is disabled.
+func (m *ModuleRegistry) scheduleJobs() error {
+  m.jobsMu.Lock()
+  m.jobsCtx, m.jobsCancel = context.WithCancel(m.ctx)
+  m.jobsStopping = false
+  m.jobsMu.Unlock()
+
+  for _, job := range m.jobs {
+    err := m.app.Cron().Add(
+      job.id, job.job.Schedule, func() {
+        if !m.nodeEnabled(job.node) {
+          return
+        }
+
+        if err := m.addJobRun(); err != nil {
+          return
+        }
+        defer m.jobsWg.Done()
+
+        if !job.begin() {
+          m.app.Logger().Warn("Skipped job run because the previous run is still in progress", "job", job.id)
+          return
+        }
+
+        m.runJob(job)
+      },
+    )
+    if err != nil {
+      return fmt.Errorf("failed to schedule job %q: %w", job.id, err)
+    }
+  }
+
+  return nil
+}
+
+// stopJobs removes all jobs from the cron, cancels the running ones and waits for them up to the stop timeout.
+func (m *ModuleRegistry) stopJobs() error {
+  m.jobsMu.Lock()
+  if m.jobsCancel == nil || m.jobsStopping {
+    m.jobsMu.Unlock()
+    return nil
+  }
+  m.jobsStopping = true
+  m.jobsMu.Unlock()
+
+  for _, job := range m.jobs {
+    m.app.Cron().Remove(job.id)
+  }
+  m.jobsCancel()
+
+  done := make(chan struct{})
+  go func() {
+    m.jobsWg.Wait()
+    close(done)
+  }()
+
+  select {
+  case <-done:
+    return nil
+  case <-time.After(m.stopTimeout):
+    return fmt.Errorf("jobs did not stop within %s", m.stopTimeout)
+  }
+}
+
+// runJob runs a job which has been marked as running by begin.
+func (m *ModuleRegistry) runJob(job *moduleJob) {
+  started := time.Now()
+  err := job.job.Run(m.jobsCtx)
+  if err != nil {
+    m.app.Logger().Error("Job failed", "job", job.id, "error", err)
+  }
+
+  job.finish(started, time.Since(started), err)
+}
+
+// begin marks the job as running and reports false if it already is.
+func (j *moduleJob) begin() bool {
+  j.mu.Lock()
+  defer j.mu.Unlock()
+
+  if j.running {
+    return false
+  }
+  j.running = true
+
+  return true
+}
+
+func (j *moduleJob) finish(started time.Time, duration time.Duration, err error) {
+  j.mu.Lock()
+  defer j.mu.Unlock()
+
+  j.running = false
+  j.runs++
+  j.lastRun = &started
+  j.lastDuration = duration
+  j.lastError = ""
+  if err != nil {
+    j.lastError = err.Error()
+  }
+}
+
+func (j *moduleJob) info() JobInfo {
+  j.mu.Lock()
+  defer j.mu.Unlock()
+
+  return JobInfo{
+    Id:           j.id,
+    Module:       j.node.path,
+    Name:         j.job.Name,
+    Schedule:     j.job.Schedule,
+    Running:      j.running,
+    Runs:         j.runs,
+    LastRun:      j.lastRun,
+    LastDuration: j.lastDuration,
+    LastError:    j.lastError,
+  }
+}
+
+type triggerJobRequest struct {
+  Id string `json:"-" path:"id"`
+}
+
+func (m *ModuleRegistry) serveJobs(e *core.RequestEvent) error {
+  return e.JSON(http.StatusOK, m.Jobs())
+}
+
+func (m *ModuleRegistry) serveTriggerJob(e *core.RequestEvent, req triggerJobRequest) (NoContent, error) {
+  err := m.TriggerJob(req.Id)
+  switch {
+  case errors.Is(err, ErrJobNotFound):
+    return NoContent{}, e.NotFoundError("Unknown job.", nil)
+  case errors.Is(err, ErrJobRunning):
+    return NoContent{}, e.Error(http.StatusConflict, "The job is already running.", nil)
+  case errors.Is(err, ErrJobModuleDisabled):
+    return NoContent{}, e.Error(http.StatusConflict, "The module of the job is disabled.", nil)
+  case errors.Is(err, ErrJobsNotRunning):
+    return NoContent{}, e.Error(http.StatusServiceUnavailable, "The jobs are not running.", nil)
+  }
+
+  return NoContent{}, err
+}
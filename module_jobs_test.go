@@ -0,0 +1,82 @@
+package pocketframework
+
+import (
+  "context"
+  "errors"
+  "testing"
+)
+
+// jobsModule is a test module with a single job which blocks until it is released or cancelled.
+type jobsModule struct {
+  testModule
+  started chan struct{}
+  release chan struct{}
+}
+
+func (j *jobsModule) Jobs() []Job {
+  return []Job{
+    {
+      Name:     "sync",
+      Schedule: "@yearly",
+      Run: func(ctx context.Context) error {
+        j.started <- struct{}{}
+        select {
+        case <-j.release:
+        case <-ctx.Done():
+        }
+        return nil
+      },
+    },
+  }
+}
+
+func TestTriggerJob(t *testing.T) {
+  module := &jobsModule{
+    testModule: testModule{name: "billing"},
+    started:    make(chan struct{}, 1),
+    release:    make(chan struct{}),
+  }
+
+  unstarted := NewModuleRegistry(nil, "/api")
+  unstarted.Register(module)
+  unstarted.roots, unstarted.ordered, _ = buildModuleTree(unstarted.modules)
+  if err := unstarted.collectJobs(); err != nil {
+    t.Fatalf("collectJobs() failed: %v", err)
+  }
+  if err := unstarted.TriggerJob("billing:sync"); !errors.Is(err, ErrJobsNotRunning) {
+    t.Errorf("TriggerJob() before start = %v, want %v", err, ErrJobsNotRunning)
+  }
+
+  registry, _ := newTestRegistry(t, module)
+
+  if err := registry.TriggerJob("billing:unknown"); !errors.Is(err, ErrJobNotFound) {
+    t.Errorf("TriggerJob() of an unknown job = %v, want %v", err, ErrJobNotFound)
+  }
+
+  if err := registry.TriggerJob("billing:sync"); err != nil {
+    t.Fatalf("TriggerJob() failed: %v", err)
+  }
+  <-module.started
+
+  if err := registry.TriggerJob("billing:sync"); !errors.Is(err, ErrJobRunning) {
+    t.Errorf("TriggerJob() of a running job = %v, want %v", err, ErrJobRunning)
+  }
+  module.release <- struct{}{}
+
+  if err := registry.SetEnabled("billing", false); err != nil {
+    t.Fatalf("SetEnabled() failed: %v", err)
+  }
+  if err := registry.TriggerJob("billing:sync"); !errors.Is(err, ErrJobModuleDisabled) {
+    t.Errorf("TriggerJob() of a disabled module = %v, want %v", err, ErrJobModuleDisabled)
+  }
+  if err := registry.SetEnabled("billing", true); err != nil {
+    t.Fatalf("SetEnabled() failed: %v", err)
+  }
+
+  if err := registry.stopJobs(); err != nil {
+    t.Fatalf("stopJobs() failed: %v", err)
+  }
+  if err := registry.TriggerJob("billing:sync"); !errors.Is(err, ErrJobsNotRunning) {
+    t.Errorf("TriggerJob() while stopping = %v, want %v", err, ErrJobsNotRunning)
+  }
+}
@@ -0,0 +1,123 @@
+package pocketframework
+
+import (
+  "context"
+  "errors"
+  "fmt"
+  "time"
+)
+
+// DefaultStopTimeout is the time a single module may take to stop unless configured otherwise.
+const DefaultStopTimeout = 10 * time.Second
+
+// ModuleState is the lifecycle state of a registered module.
+type ModuleState string
+
+const (
+  ModuleStateRegistered ModuleState = "registered"
+  ModuleStateStarting   ModuleState = "starting"
+  ModuleStateStarted    ModuleState = "started"
+  ModuleStateStopping   ModuleState = "stopping"
+  ModuleStateStopped    ModuleState = "stopped"
+  ModuleStateFailed     ModuleState = "failed"
+)
+
+// State returns the lifecycle state of the module with the given path.
+func (m *ModuleRegistry) State(path string) (ModuleState, bool) {
+  m.mu.RLock()
+  defer m.mu.RUnlock()
+
+  node := m.findNode(path)
+  if node == nil {
+    return "", false
+  }
+
+  return node.state, true
+}
+
+// startModules starts all modules in dependency order. If a module fails to start, the already started modules are
+// stopped again.
+func (m *ModuleRegistry) startModules() error {
+  m.ctx, m.cancel = context.WithCancel(context.Background())
+
+  err := m.forEachModule(
+    func(node *moduleNode) error {
+      m.setState(node, ModuleStateStarting)
+
+      if starter, ok := node.module.(Starter); ok {
+        if err := starter.Start(m.ctx); err != nil {
+          m.setState(node, ModuleStateFailed)
+          return fmt.Errorf("failed to start module %q: %w", node.path, err)
+        }
+      }
+
+      m.setState(node, ModuleStateStarted)
+      m.started = append(m.started, node)
+
+      return nil
+    },
+  )
+  if err != nil {
+    return errors.Join(err, m.stopModules())
+  }
+
+  return nil
+}
+
+// stopModules stops all started modules in reverse start order. Every module gets its own timeout and all errors
+// are reported together.
+func (m *ModuleRegistry) stopModules() error {
+  var errs []error
+
+  for i := len(m.started) - 1; i >= 0; i-- {
+    node := m.started[i]
+    m.setState(node, ModuleStateStopping)
+
+    if err := m.stopModule(node); err != nil {
+      m.setState(node, ModuleStateFailed)
+      errs = append(errs, err)
+      continue
+    }
+
+    m.setState(node, ModuleStateStopped)
+  }
+  m.started = nil
+
+  if m.cancel != nil {
+    m.cancel()
+  }
+
+  return errors.Join(errs...)
+}
+
+func (m *ModuleRegistry) stopModule(node *moduleNode) error {
+  stopper, ok := node.module.(Stopper)
+  if !ok {
+    return nil
+  }
+
+  ctx, cancel := context.WithTimeout(context.Background(), m.stopTimeout)
+  defer cancel()
+
+  done := make(chan error, 1)
+  go func() {
+    done <- stopper.Stop(ctx)
+  }()
+
+  select {
+  case err := <-done:
+    if err != nil {
+      return fmt.Errorf("failed to stop module %q: %w", node.path, err)
+    }
+    return nil
+  case <-ctx.Done():
+    return fmt.Errorf("module %q did not stop within %s", node.path, m.stopTimeout)
+  }
+}
+
+func (m *ModuleRegistry) setState(node *moduleNode, state ModuleState) {
+  m.mu.Lock()
+  defer m.mu.Unlock()
+
+  node.state = state
+}
@@ -0,0 +1,229 @@
+package pocketframework
+
+import (
+  "fmt"
+  "time"
+
+  "github.com/pocketbase/dbx"
+  "github.com/pocketbase/pocketbase/core"
+)
+
+// MigrationsTable is the table used to keep track of the applied module migrations.
+const MigrationsTable = "_pocketframework_migrations"
+
+// Migration is a single database migration owned by a module.
+type Migration struct {
+  // Name identifies the migration within its module, e.g. "1700000000_create_invoices".
+  Name string
+
+  // Up applies the migration. It is executed inside a transaction.
+  Up func(txApp core.App) error
+
+  // Down reverts the migration. It is executed inside a transaction and might be nil.
+  Down func(txApp core.App) error
+}
+
+// ModuleMigrationsStatus reports the applied and pending migrations of a single module.
+type ModuleMigrationsStatus struct {
+  Module  string   `json:"module"`
+  Applied []string `json:"applied"`
+  Pending []string `json:"pending"`
+}
+
+type migrationSet struct {
+  module     string
+  migrations []Migration
+}
+
+// MigrationsStatus returns the applied and pending migrations of every module with migrations.
+func (m *ModuleRegistry) MigrationsStatus() ([]ModuleMigrationsStatus, error) {
+  if err := createMigrationsTable(m.app); err != nil {
+    return nil, err
+  }
+
+  result := []ModuleMigrationsStatus{}
+  for _, set := range m.migrationSets() {
+    applied, err := appliedMigrations(m.app, set.module)
+    if err != nil {
+      return nil, err
+    }
+
+    status := ModuleMigrationsStatus{
+      Module:  set.module,
+      Applied: []string{},
+      Pending: []string{},
+    }
+
+    for _, migration := range set.migrations {
+      if applied[migration.Name] {
+        status.Applied = append(status.Applied, migration.Name)
+      } else {
+        status.Pending = append(status.Pending, migration.Name)
+      }
+    }
+
+    result = append(result, status)
+  }
+
+  return result, nil
+}
+
+// RevertLastMigration reverts the most recently applied migration of the module with the given path.
+func (m *ModuleRegistry) RevertLastMigration(path string) error {
+  if err := createMigrationsTable(m.app); err != nil {
+    return err
+  }
+
+  var set *migrationSet
+  for _, s := range m.migrationSets() {
+    if s.module == path {
+      set = &s
+      break
+    }
+  }
+  if set == nil {
+    return fmt.Errorf("module %q has no migrations", path)
+  }
+
+  var name string
+  err := m.app.DB().
+    Select("name").
+    From(MigrationsTable).
+    Where(dbx.HashExp{"module": path}).
+    OrderBy("applied DESC", "rowid DESC").
+    Limit(1).
+    Row(&name)
+  if err != nil {
+    return fmt.Errorf("module %q has no applied migrations: %w", path, err)
+  }
+
+  for _, migration := range set.migrations {
+    if migration.Name != name {
+      continue
+    }
+
+    if migration.Down == nil {
+      return fmt.Errorf("migration %q of module %q cannot be reverted", name, path)
+    }
+
+    return m.app.RunInTransaction(
+      func(txApp core.App) error {
+        if err := migration.Down(txApp); err != nil {
+          return fmt.Errorf("failed to revert migration %q of module %q: %w", name, path, err)
+        }
+
+        _, err := txApp.DB().Delete(MigrationsTable, dbx.HashExp{"module": path, "name": name}).Execute()
+        return err
+      },
+    )
+  }
+
+  return fmt.Errorf("applied migration %q of module %q is no longer registered", name, path)
+}
+
+// applyMigrations applies all pending migrations in dependency order, parents before their children.
+func (m *ModuleRegistry) applyMigrations() error {
+  if err := createMigrationsTable(m.app); err != nil {
+    return err
+  }
+
+  for _, set := range m.migrationSets() {
+    if err := applyMigrationSet(m.app, set); err != nil {
+      return err
+    }
+  }
+
+  return nil
+}
+
+func (m *ModuleRegistry) migrationSets() []migrationSet {
+  sets := []migrationSet{{module: frameworkProvider, migrations: frameworkMigrations()}}
+
+  _ = m.forEachModule(
+    func(node *moduleNode) error {
+      if moduleWithMigrations, ok := node.module.(ModuleWithMigrations); ok {
+        sets = append(sets, migrationSet{module: node.path, migrations: moduleWithMigrations.Migrations()})
+      }
+      return nil
+    },
+  )
+
+  return sets
+}
+
+func applyMigrationSet(app core.App, set migrationSet) error {
+  names := map[string]bool{}
+  for _, migration := range set.migrations {
+    if migration.Name == "" || migration.Up == nil {
+      return fmt.Errorf("module %q has a migration without name or up function", set.module)
+    }
+    if names[migration.Name] {
+      return fmt.Errorf("module %q has multiple migrations named %q", set.module, migration.Name)
+    }
+    names[migration.Name] = true
+  }
+
+  applied, err := appliedMigrations(app, set.module)
+  if err != nil {
+    return err
+  }
+
+  for _, migration := range set.migrations {
+    if applied[migration.Name] {
+      continue
+    }
+
+    err := app.RunInTransaction(
+      func(txApp core.App) error {
+        if err := migration.Up(txApp); err != nil {
+          return err
+        }
+
+        _, err := txApp.DB().Insert(
+          MigrationsTable, dbx.Params{
+            "module":  set.module,
+            "name":    migration.Name,
+            "applied": time.Now().UnixMicro(),
+          },
+        ).Execute()
+        return err
+      },
+    )
+    if err != nil {
+      return fmt.Errorf("failed to apply migration %q of module %q: %w", migration.Name, set.module, err)
+    }
+  }
+
+  return nil
+}
+
+func appliedMigrations(app core.App, module string) (map[string]bool, error) {
+  names := []string{}
+  err := app.DB().
+    Select("name").
+    From(MigrationsTable).
+    Where(dbx.HashExp{"module": module}).
+    Column(&names)
+  if err != nil {
+    return nil, fmt.Errorf("failed to load the applied migrations of module %q: %w", module, err)
+  }
+
+  applied := make(map[string]bool, len(names))
+  for _, name := range names {
+    applied[name] = true
+  }
+
+  return applied, nil
+}
+
+func createMigrationsTable(app core.App) error {
+  _, err := app.DB().NewQuery(
+    "CREATE TABLE IF NOT EXISTS {{" + MigrationsTable + "}} (" +
+      "[[module]] TEXT NOT NULL, " +
+      "[[name]] TEXT NOT NULL, " +
+      "[[applied]] INTEGER NOT NULL, " +
+      "PRIMARY KEY ([[module]], [[name]]))",
+  ).Execute()
+
+  return err
+}
@@ -0,0 +1,151 @@
+package pocketframework
+
+import (
+  "errors"
+  "reflect"
+  "strings"
+  "testing"
+
+  "github.com/pocketbase/pocketbase/core"
+)
+
+// migrationsModule is a test module whose migrations record every run in calls.
+type migrationsModule struct {
+  testModule
+  migrations []Migration
+}
+
+func (m *migrationsModule) Migrations() []Migration {
+  return m.migrations
+}
+
+func recordingMigration(calls *[]string, module string, name string) Migration {
+  return Migration{
+    Name: name,
+    Up: func(txApp core.App) error {
+      *calls = append(*calls, "up "+module+":"+name)
+      return nil
+    },
+    Down: func(txApp core.App) error {
+      *calls = append(*calls, "down "+module+":"+name)
+      return nil
+    },
+  }
+}
+
+func TestMigrations(t *testing.T) {
+  calls := []string{}
+
+  billing := &migrationsModule{
+    testModule: testModule{name: "billing", dependsOn: []string{"users"}},
+    migrations: []Migration{recordingMigration(&calls, "billing", "1_init")},
+  }
+  users := &migrationsModule{
+    testModule: testModule{name: "users"},
+    migrations: []Migration{recordingMigration(&calls, "users", "1_init"), recordingMigration(&calls, "users", "2_profiles")},
+  }
+
+  registry, _ := newTestRegistry(t, billing, users)
+
+  // migrations with the same name are tracked per module and applied in dependency order
+  expected := []string{"up users:1_init", "up users:2_profiles", "up billing:1_init"}
+  if !reflect.DeepEqual(calls, expected) {
+    t.Fatalf("applied migrations = %v, want %v", calls, expected)
+  }
+
+  // applied migrations are not applied again, new ones are
+  calls = calls[:0]
+  billing.migrations = append(billing.migrations, recordingMigration(&calls, "billing", "2_discounts"))
+  if err := registry.applyMigrations(); err != nil {
+    t.Fatalf("applyMigrations() failed: %v", err)
+  }
+  if expected := []string{"up billing:2_discounts"}; !reflect.DeepEqual(calls, expected) {
+    t.Fatalf("applied migrations = %v, want %v", calls, expected)
+  }
+
+  calls = calls[:0]
+  if err := registry.RevertLastMigration("billing"); err != nil {
+    t.Fatalf("RevertLastMigration() failed: %v", err)
+  }
+  if expected := []string{"down billing:2_discounts"}; !reflect.DeepEqual(calls, expected) {
+    t.Fatalf("reverted migrations = %v, want %v", calls, expected)
+  }
+
+  status, err := registry.MigrationsStatus()
+  if err != nil {
+    t.Fatalf("MigrationsStatus() failed: %v", err)
+  }
+  expectedStatus := []ModuleMigrationsStatus{
+    {Module: frameworkProvider, Applied: []string{"1_create_module_flags", "2_create_module_settings", "3_create_queue"}, Pending: []string{}},
+    {Module: "users", Applied: []string{"1_init", "2_profiles"}, Pending: []string{}},
+    {Module: "billing", Applied: []string{"1_init"}, Pending: []string{"2_discounts"}},
+  }
+  if !reflect.DeepEqual(status[:len(expectedStatus)], expectedStatus) {
+    t.Errorf("MigrationsStatus() = %+v, want %+v", status, expectedStatus)
+  }
+
+  if err := registry.RevertLastMigration("unknown"); err == nil {
+    t.Errorf("RevertLastMigration() of a module without migrations succeeded")
+  }
+}
+
+func TestMigrationErrors(t *testing.T) {
+  tests := []struct {
+    name       string
+    migrations []Migration
+    err        string
+  }{
+    {
+      name:       "missing up function",
+      migrations: []Migration{{Name: "1_init"}},
+      err:        `module "billing" has a migration without name or up function`,
+    },
+    {
+      name: "duplicate names",
+      migrations: []Migration{
+        {Name: "1_init", Up: func(txApp core.App) error { return nil }},
+        {Name: "1_init", Up: func(txApp core.App) error { return nil }},
+      },
+      err: `module "billing" has multiple migrations named "1_init"`,
+    },
+    {
+      name: "failing migration",
+      migrations: []Migration{
+        {
+          Name: "1_init",
+          Up: func(txApp core.App) error {
+            return errors.New("no space left")
+          },
+        },
+      },
+      err: `failed to apply migration "1_init" of module "billing": no space left`,
+    },
+  }
+
+  for _, tt := range tests {
+    t.Run(
+      tt.name, func(t *testing.T) {
+        registry, app := newTestRegistry(t)
+        registry.Register(&migrationsModule{testModule: testModule{name: "billing"}, migrations: tt.migrations})
+
+        var err error
+        if registry.roots, registry.ordered, err = buildModuleTree(registry.modules); err != nil {
+          t.Fatal(err)
+        }
+
+        err = registry.applyMigrations()
+        if err == nil || !strings.Contains(err.Error(), tt.err) {
+          t.Fatalf("applyMigrations() = %v, want an error containing %q", err, tt.err)
+        }
+
+        applied, err := appliedMigrations(app, "billing")
+        if err != nil {
+          t.Fatal(err)
+        }
+        if len(applied) > 0 {
+          t.Errorf("failed migrations have been recorded as applied: %v", applied)
+        }
+      },
+    )
+  }
+}
@@ -1,22 +1,83 @@
 package pocketframework
 
 import (
-  "github.com/pocketbase/pocketbase/apis"
+  "context"
+  "errors"
+  "net/http"
+  "sync"
+  "time"
+
   "github.com/pocketbase/pocketbase/core"
 )
 
 type ModuleRegistry struct {
-  modules   []Module
-  app       core.App
-  apiPrefix string
+  modules     []Module
+  roots       []*moduleNode
+  ordered     []*moduleNode
+  started     []*moduleNode
+  app         core.App
+  apiPrefix   string
+  stopTimeout time.Duration
+  configFile  string
+  envPrefix   string
+  services    *ServiceContainer
+  groups      []GroupDefinition
+  routes      *routeTable
+  openAPI     OpenAPIConfig
+  flags       map[string]moduleFlag
+  jobs        []*moduleJob
+  queue       *Queue
+  events      *EventBus
+  metrics     *Metrics
+  tracer      *Tracer
+
+  disabledStatus        int
+  healthTimeout         time.Duration
+  persistedSettings     map[string]bool
+  destructiveSchemaSync bool
+
+  mu     sync.RWMutex
+  ctx    context.Context
+  cancel context.CancelFunc
+
+  jobsMu       sync.Mutex
+  jobsCtx      context.Context
+  jobsCancel   context.CancelFunc
+  jobsStopping bool
+  jobsWg       sync.WaitGroup
+}
+
+// RegistryOption configures optional behavior of a ModuleRegistry.
+type RegistryOption func(m *ModuleRegistry)
+
+// WithStopTimeout sets how long a single module may take to stop. Defaults to DefaultStopTimeout.
+func WithStopTimeout(timeout time.Duration) RegistryOption {
+  return func(m *ModuleRegistry) {
+    m.stopTimeout = timeout
+  }
 }
 
-func NewModuleRegistry(app core.App, apiPrefix string) *ModuleRegistry {
-  return &ModuleRegistry{
-    modules:   []Module{},
-    app:       app,
-    apiPrefix: apiPrefix,
+func NewModuleRegistry(app core.App, apiPrefix string, opts ...RegistryOption) *ModuleRegistry {
+  m := &ModuleRegistry{
+    modules:     []Module{},
+    app:         app,
+    apiPrefix:   apiPrefix,
+    stopTimeout: DefaultStopTimeout,
+    services:    NewServiceContainer(),
+    queue:       newQueue(app),
+    events:      newEventBus(app),
+    metrics:     newMetrics(),
+    tracer:      &Tracer{app: app},
+
+    disabledStatus: http.StatusNotFound,
+    healthTimeout:  DefaultHealthCheckTimeout,
   }
+
+  for _, opt := range opts {
+    opt(m)
+  }
+
+  return m
 }
 
 // Register registers a module in the module registry.
@@ -24,34 +85,117 @@ func (m *ModuleRegistry) Register(module Module) {
   m.modules = append(m.modules, module)
 }
 
-// Init adds the module registry to the pocketbase app.
+// Init adds the module registry to the pocketbase app. Modules are initialized in dependency order.
 func (m *ModuleRegistry) Init() error {
-  for _, module := range m.modules {
-    if err := registerModuleHooks(module, m.app); err != nil {
-      return err
-    }
+  if err := m.validateGroupDefinitions(); err != nil {
+    return err
+  }
+
+  roots, ordered, err := buildModuleTree(m.modules)
+  if err != nil {
+    return err
   }
+  m.roots = roots
+  m.ordered = ordered
+
+  if err := m.loadConfigs(); err != nil {
+    return err
+  }
+
+  if err := m.loadDefaultSettings(); err != nil {
+    return err
+  }
+
+  if err := m.loadFlagEnv(); err != nil {
+    return err
+  }
+
+  if err := m.collectJobs(); err != nil {
+    return err
+  }
+
+  if err := m.collectQueueHandlers(); err != nil {
+    return err
+  }
+
+  if _, err := m.collectionDeclarations(); err != nil {
+    return err
+  }
+
+  if err := m.provideServices(); err != nil {
+    return err
+  }
+
+  if err := m.forEachModule(m.registerModuleHooks); err != nil {
+    return err
+  }
+
+  m.bindFlagReload()
+  m.bindSettingsReload()
+
+  m.app.OnBootstrap().BindFunc(
+    func(e *core.BootstrapEvent) error {
+      if err := e.Next(); err != nil {
+        return err
+      }
+
+      m.tracer.traceDB(m.app.ConcurrentDB())
+      m.tracer.traceDB(m.app.NonconcurrentDB())
+
+      if err := m.applyMigrations(); err != nil {
+        return err
+      }
+
+      if err := m.ReloadFlags(); err != nil {
+        return err
+      }
+
+      if err := m.ReloadSettings(); err != nil {
+        return err
+      }
+
+      if err := m.syncSchema(); err != nil {
+        return err
+      }
+
+      if err := m.startModules(); err != nil {
+        return err
+      }
+
+      return m.scheduleJobs()
+    },
+  )
+
+  m.app.OnTerminate().BindFunc(
+    func(e *core.TerminateEvent) error {
+      return errors.Join(
+        m.queue.drain(m.stopTimeout),
+        m.stopJobs(),
+        m.events.wait(m.stopTimeout),
+        m.stopModules(),
+        m.tracer.close(),
+        e.Next(),
+      )
+    },
+  )
 
   m.app.OnServe().BindFunc(
     func(se *core.ServeEvent) error {
-      baseGroup := se.Router.Group(m.apiPrefix)
-
-      authenticatedGroup := se.Router.Group(m.apiPrefix)
-      authenticatedGroup.Bind(apis.RequireAuth())
+      if m.tracer.Enabled() {
+        se.Router.Bind(m.tracer.requestTracingMiddleware())
+      }
 
-      adminGroup := se.Router.Group(m.apiPrefix)
-      adminGroup.Bind(apis.RequireSuperuserAuth())
+      if err := m.mountRoutes(se.Router); err != nil {
+        return err
+      }
 
-      baseGroups := RouterGroups{
-        Public:        baseGroup,
-        Authenticated: authenticatedGroup,
-        Admin:         adminGroup,
+      if m.openAPI.Path != "" {
+        se.Router.GET(m.openAPI.Path, m.serveOpenAPI)
       }
 
-      for _, module := range m.modules {
-        if err := serveModule(module, baseGroups); err != nil {
-          return err
-        }
+      // the queue only runs while serving, so it does not handle jobs during one-off commands
+      if err := m.queue.start(m.ctx); err != nil {
+        return err
       }
 
       return se.Next()
@@ -61,35 +205,56 @@ func (m *ModuleRegistry) Init() error {
   return nil
 }
 
-func registerModuleHooks(module Module, app core.App) error {
-  if err := module.RegisterHooks(app); err != nil {
-    return err
+// forEachModule calls fn for every module in dependency order, parents before their children, and stops at the first
+// error.
+func (m *ModuleRegistry) forEachModule(fn func(node *moduleNode) error) error {
+  for _, node := range m.ordered {
+    if err := fn(node); err != nil {
+      return err
+    }
   }
 
-  if moduleWithChildren, ok := module.(ModuleWithChildren); ok {
-    for _, childModule := range moduleWithChildren.Children() {
-      if err := registerModuleHooks(childModule, app); err != nil {
-        return err
-      }
+  return nil
+}
+
+// findNode returns the module with the given path or nil if there is none.
+func (m *ModuleRegistry) findNode(path string) *moduleNode {
+  for _, node := range m.ordered {
+    if node.path == path {
+      return node
     }
   }
 
   return nil
 }
 
-func serveModule(module Module, baseGroups RouterGroups) error {
-  groups := baseGroups.WithPrefix(module.Prefix())
-  if err := module.RegisterRoutes(groups); err != nil {
-    return err
+func (m *ModuleRegistry) registerModuleHooks(node *moduleNode) error {
+  // the handlers of disabled modules are skipped
+  node.hooks.active = func() bool {
+    return m.nodeEnabled(node)
   }
+  node.hooks.instrument = func(hookName string, event any) func(err error) {
+    started := time.Now()
+    span, restore := m.tracer.startHookSpan(node.path, hookName, event)
 
-  if moduleWithChildren, ok := module.(ModuleWithChildren); ok {
-    for _, childModule := range moduleWithChildren.Children() {
-      if err := serveModule(childModule, groups); err != nil {
-        return err
-      }
+    return func(err error) {
+      restore()
+      m.metrics.observeHook(node.path, hookName, time.Since(started), err)
+      span.Finish(err)
     }
   }
 
-  return nil
+  return node.module.RegisterHooks(newScopedHooks(m.app, node.hooks))
+}
+
+// serveModule registers the routes of a single module within the groups of its parent and returns the groups of the
+// module, which its children are served in.
+func (m *ModuleRegistry) serveModule(node *moduleNode, parentGroups RouterGroups) (RouterGroups, error) {
+  groups := parentGroups.forModule(node.path, node.module.Prefix())
+  groups.Bind(m.moduleFlagMiddleware(node))
+  if moduleWithMiddleware, ok := node.module.(ModuleWithMiddleware); ok {
+    groups.Bind(moduleWithMiddleware.Middlewares()...)
+  }
+
+  return groups, node.module.RegisterRoutes(groups)
 }
@@ -0,0 +1,467 @@
+package pocketframework
+
+import (
+  "bytes"
+  "context"
+  "database/sql"
+  "encoding/json"
+  "errors"
+  "fmt"
+  "net/http"
+  "reflect"
+  "strings"
+  "sync"
+
+  validation "github.com/go-ozzo/ozzo-validation/v4"
+  "github.com/pocketbase/pocketbase/core"
+  "github.com/pocketbase/pocketbase/tools/hook"
+)
+
+// SettingsCollection is the system collection used to persist the module settings which have been changed at
+// runtime.
+const SettingsCollection = "_pocketframework_module_settings"
+
+// settingsWriteKey marks the records saved by SetSettings and ResetSettings, which apply the change themselves.
+type settingsWriteKey struct{}
+
+// ModuleSettings can be embedded into a module to give it typed settings which can be changed at runtime through the
+// admin endpoints or SetSettings, e.g. a trial length or an upload limit.
+//
+// Fields are initialized from the "default" struct tag, values changed at runtime are persisted in the database and
+// override the defaults. If the settings implement validation.Validatable, they are validated before every change.
+type ModuleSettings[T any] struct {
+  mu       sync.RWMutex
+  settings T
+  onChange *hook.Hook[*SettingsChangeEvent[T]]
+}
+
+// SettingsChangeEvent is triggered when the settings of a module have changed.
+type SettingsChangeEvent[T any] struct {
+  hook.Event
+
+  Module   string
+  Previous T
+  Settings T
+}
+
+// SettingsTarget holds the settings of a module. It is implemented by ModuleSettings.
+type SettingsTarget interface {
+  defaultSettings() (any, []ConfigFieldError)
+  decodeSettings(data []byte, strict bool) (any, []ConfigFieldError)
+  currentSettings() any
+  applySettings(module string, settings any, notify bool) error
+}
+
+// SettingsInfo describes the current settings of a module.
+type SettingsInfo struct {
+  Module   string `json:"module"`
+  Settings any    `json:"settings"`
+  Defaults any    `json:"defaults"`
+
+  // Persisted is true if the settings have been changed at runtime and override the defaults.
+  Persisted bool `json:"persisted"`
+}
+
+// SettingsError lists every invalid field of the settings of a module.
+type SettingsError struct {
+  Module string
+  Fields []ConfigFieldError
+}
+
+func (e *SettingsError) Error() string {
+  lines := make([]string, 0, len(e.Fields))
+  for _, field := range e.Fields {
+    lines = append(lines, fmt.Sprintf("%s: %s", field.Field, field.Error))
+  }
+
+  return fmt.Sprintf("invalid settings of module %q:\n  %s", e.Module, strings.Join(lines, "\n  "))
+}
+
+// SettingsTarget implements ModuleWithSettings.
+func (s *ModuleSettings[T]) SettingsTarget() SettingsTarget {
+  return s
+}
+
+// Settings returns the current settings.
+func (s *ModuleSettings[T]) Settings() T {
+  s.mu.RLock()
+  defer s.mu.RUnlock()
+
+  return s.settings
+}
+
+// OnSettingsChange is triggered after the settings have been changed, once the new settings are returned by
+// Settings. It is also triggered when the app is bootstrapped if the persisted settings differ from the defaults.
+func (s *ModuleSettings[T]) OnSettingsChange() *hook.Hook[*SettingsChangeEvent[T]] {
+  s.mu.Lock()
+  defer s.mu.Unlock()
+
+  if s.onChange == nil {
+    s.onChange = &hook.Hook[*SettingsChangeEvent[T]]{}
+  }
+
+  return s.onChange
+}
+
+func (s *ModuleSettings[T]) defaultSettings() (any, []ConfigFieldError) {
+  var settings T
+
+  value := reflect.ValueOf(&settings).Elem()
+  if value.Kind() != reflect.Struct {
+    return nil, []ConfigFieldError{{Field: "-", Error: fmt.Sprintf("settings must be a struct, got %T", settings)}}
+  }
+
+  if errs := applyDefaults(value, ""); len(errs) > 0 {
+    return nil, errs
+  }
+
+  return settings, nil
+}
+
+// decodeSettings decodes data onto the defaults and validates the result. Unknown fields are rejected if strict is
+// true, so typos are reported to admins while persisted fields of removed settings are ignored.
+func (s *ModuleSettings[T]) decodeSettings(data []byte, strict bool) (any, []ConfigFieldError) {
+  defaults, errs := s.defaultSettings()
+  if len(errs) > 0 {
+    return nil, errs
+  }
+  settings := defaults.(T)
+
+  decoder := json.NewDecoder(bytes.NewReader(data))
+  if strict {
+    decoder.DisallowUnknownFields()
+  }
+  if err := decoder.Decode(&settings); err != nil {
+    return nil, []ConfigFieldError{{Field: "-", Error: "invalid settings: " + err.Error()}}
+  }
+
+  if validatable, ok := any(&settings).(validation.Validatable); ok {
+    if errs := validationFieldErrors("", validatable.Validate()); len(errs) > 0 {
+      return nil, errs
+    }
+  }
+
+  return settings, nil
+}
+
+func (s *ModuleSettings[T]) currentSettings() any {
+  return s.Settings()
+}
+
+func (s *ModuleSettings[T]) applySettings(module string, settings any, notify bool) error {
+  s.mu.Lock()
+  previous := s.settings
+  s.settings = settings.(T)
+  onChange := s.onChange
+  s.mu.Unlock()
+
+  if !notify || onChange == nil || reflect.DeepEqual(previous, settings) {
+    return nil
+  }
+
+  return onChange.Trigger(&SettingsChangeEvent[T]{Module: module, Previous: previous, Settings: settings.(T)})
+}
+
+// Settings returns the current settings of the module with the given path.
+func (m *ModuleRegistry) Settings(path string) (SettingsInfo, error) {
+  node := m.findNode(path)
+  if node == nil {
+    return SettingsInfo{}, fmt.Errorf("unknown module %q", path)
+  }
+
+  target := moduleSettingsTarget(node.module)
+  if target == nil {
+    return SettingsInfo{}, fmt.Errorf("module %q has no settings", path)
+  }
+
+  return m.settingsInfo(node, target), nil
+}
+
+// AllSettings returns the current settings of all modules which have settings.
+func (m *ModuleRegistry) AllSettings() []SettingsInfo {
+  infos := []SettingsInfo{}
+  for _, root := range m.roots {
+    _ = root.walk(
+      func(node *moduleNode) error {
+        if target := moduleSettingsTarget(node.module); target != nil {
+          infos = append(infos, m.settingsInfo(node, target))
+        }
+        return nil
+      },
+    )
+  }
+
+  return infos
+}
+
+// SetSettings replaces the settings of the module with the given path with the given JSON object and persists them.
+// Omitted fields are reset to their defaults. Invalid settings are rejected with a *SettingsError.
+//
+// The change takes effect immediately and the OnSettingsChange handlers of the module are triggered. Their errors
+// are returned, but do not revert the change.
+func (m *ModuleRegistry) SetSettings(path string, data json.RawMessage) error {
+  node := m.findNode(path)
+  if node == nil {
+    return fmt.Errorf("unknown module %q", path)
+  }
+
+  target := moduleSettingsTarget(node.module)
+  if target == nil {
+    return fmt.Errorf("module %q has no settings", path)
+  }
+
+  settings, errs := target.decodeSettings(data, true)
+  if len(errs) > 0 {
+    return &SettingsError{Module: path, Fields: errs}
+  }
+
+  value, err := json.Marshal(settings)
+  if err != nil {
+    return fmt.Errorf("failed to encode the settings of module %q: %w", path, err)
+  }
+
+  record, err := m.app.FindFirstRecordByData(SettingsCollection, "module", path)
+  if errors.Is(err, sql.ErrNoRows) {
+    collection, err := m.app.FindCachedCollectionByNameOrId(SettingsCollection)
+    if err != nil {
+      return fmt.Errorf("failed to load the module settings collection: %w", err)
+    }
+    record = core.NewRecord(collection)
+    record.Set("module", path)
+  } else if err != nil {
+    return fmt.Errorf("failed to load the settings of module %q: %w", path, err)
+  }
+
+  record.Set("value", json.RawMessage(value))
+  if err := m.app.SaveWithContext(context.WithValue(context.Background(), settingsWriteKey{}, true), record); err != nil {
+    return fmt.Errorf("failed to persist the settings of module %q: %w", path, err)
+  }
+
+  m.mu.Lock()
+  m.persistedSettings[path] = true
+  m.mu.Unlock()
+
+  return target.applySettings(path, settings, true)
+}
+
+// ResetSettings removes the persisted settings of the module with the given path, so the defaults apply again.
+func (m *ModuleRegistry) ResetSettings(path string) error {
+  node := m.findNode(path)
+  if node == nil {
+    return fmt.Errorf("unknown module %q", path)
+  }
+
+  target := moduleSettingsTarget(node.module)
+  if target == nil {
+    return fmt.Errorf("module %q has no settings", path)
+  }
+
+  record, err := m.app.FindFirstRecordByData(SettingsCollection, "module", path)
+  if err != nil && !errors.Is(err, sql.ErrNoRows) {
+    return fmt.Errorf("failed to load the settings of module %q: %w", path, err)
+  }
+  if record != nil {
+    if err := m.app.DeleteWithContext(context.WithValue(context.Background(), settingsWriteKey{}, true), record); err != nil {
+      return fmt.Errorf("failed to reset the settings of module %q: %w", path, err)
+    }
+  }
+
+  m.mu.Lock()
+  delete(m.persistedSettings, path)
+  m.mu.Unlock()
+
+  defaults, _ := target.defaultSettings()
+  return target.applySettings(path, defaults, true)
+}
+
+// ReloadSettings reloads the persisted settings from the database, e.g. after they have been changed by another
+// instance. Persisted settings which are no longer valid are logged and replaced by the defaults. Changes to the
+// settings collection made through the dashboard or the records API are reloaded automatically.
+func (m *ModuleRegistry) ReloadSettings() error {
+  records, err := m.app.FindAllRecords(SettingsCollection)
+  if err != nil {
+    return fmt.Errorf("failed to load the module settings: %w", err)
+  }
+
+  persisted := make(map[string][]byte, len(records))
+  for _, record := range records {
+    var value json.RawMessage
+    if err := record.UnmarshalJSONField("value", &value); err != nil {
+      return fmt.Errorf("failed to decode the persisted settings of module %q: %w", record.GetString("module"), err)
+    }
+    persisted[record.GetString("module")] = value
+  }
+
+  var errs []error
+  persistedSettings := map[string]bool{}
+  for _, root := range m.roots {
+    _ = root.walk(
+      func(node *moduleNode) error {
+        target := moduleSettingsTarget(node.module)
+        if target == nil {
+          return nil
+        }
+
+        settings, _ := target.defaultSettings()
+        if value, ok := persisted[node.path]; ok {
+          decoded, fieldErrs := target.decodeSettings(value, false)
+          if len(fieldErrs) > 0 {
+            m.app.Logger().Warn(
+              "Ignoring the invalid persisted settings of a module",
+              "module", node.path,
+              "error", (&SettingsError{Module: node.path, Fields: fieldErrs}).Error(),
+            )
+          } else {
+            settings = decoded
+            persistedSettings[node.path] = true
+          }
+        }
+
+        if err := target.applySettings(node.path, settings, true); err != nil {
+          errs = append(errs, fmt.Errorf("failed to apply the settings of module %q: %w", node.path, err))
+        }
+        return nil
+      },
+    )
+  }
+
+  m.mu.Lock()
+  m.persistedSettings = persistedSettings
+  m.mu.Unlock()
+
+  return errors.Join(errs...)
+}
+
+// bindSettingsReload reloads the settings whenever a record of the settings collection is changed by anything else
+// than SetSettings and ResetSettings.
+func (m *ModuleRegistry) bindSettingsReload() {
+  reload := func(e *core.RecordEvent) error {
+    if err := e.Next(); err != nil {
+      return err
+    }
+    if e.Context != nil && e.Context.Value(settingsWriteKey{}) != nil {
+      return nil
+    }
+
+    if err := m.ReloadSettings(); err != nil {
+      e.App.Logger().Warn("Failed to reload the module settings", "error", err)
+    }
+    return nil
+  }
+
+  m.app.OnRecordAfterCreateSuccess(SettingsCollection).BindFunc(reload)
+  m.app.OnRecordAfterUpdateSuccess(SettingsCollection).BindFunc(reload)
+  m.app.OnRecordAfterDeleteSuccess(SettingsCollection).BindFunc(reload)
+}
+
+// loadDefaultSettings initializes the settings of all modules with their defaults and reports every invalid default
+// at once.
+func (m *ModuleRegistry) loadDefaultSettings() error {
+  m.persistedSettings = map[string]bool{}
+
+  configErr := &ConfigError{}
+  for _, root := range m.roots {
+    _ = root.walk(
+      func(node *moduleNode) error {
+        target := moduleSettingsTarget(node.module)
+        if target == nil {
+          return nil
+        }
+
+        defaults, errs := target.defaultSettings()
+        for _, fieldErr := range errs {
+          fieldErr.Module = node.path
+          configErr.Fields = append(configErr.Fields, fieldErr)
+        }
+        if len(errs) == 0 {
+          _ = target.applySettings(node.path, defaults, false)
+        }
+        return nil
+      },
+    )
+  }
+
+  if len(configErr.Fields) > 0 {
+    return configErr
+  }
+
+  return nil
+}
+
+func (m *ModuleRegistry) settingsInfo(node *moduleNode, target SettingsTarget) SettingsInfo {
+  defaults, _ := target.defaultSettings()
+
+  m.mu.RLock()
+  persisted := m.persistedSettings[node.path]
+  m.mu.RUnlock()
+
+  return SettingsInfo{
+    Module:    node.path,
+    Settings:  target.currentSettings(),
+    Defaults:  defaults,
+    Persisted: persisted,
+  }
+}
+
+func moduleSettingsTarget(module Module) SettingsTarget {
+  if moduleWithSettings, ok := module.(ModuleWithSettings); ok {
+    return moduleWithSettings.SettingsTarget()
+  }
+
+  return nil
+}
+
+type settingsRequest struct {
+  Module string `json:"-" path:"module"`
+}
+
+type setSettingsRequest struct {
+  Module   string          `json:"-" path:"module"`
+  Settings json.RawMessage `json:"settings"`
+}
+
+func (m *ModuleRegistry) serveAllSettings(e *core.RequestEvent) error {
+  return e.JSON(http.StatusOK, m.AllSettings())
+}
+
+func (m *ModuleRegistry) serveSettings(e *core.RequestEvent, req settingsRequest) (SettingsInfo, error) {
+  node := m.findNode(req.Module)
+  if node == nil || moduleSettingsTarget(node.module) == nil {
+    return SettingsInfo{}, e.NotFoundError("Unknown module or module without settings.", nil)
+  }
+
+  return m.Settings(req.Module)
+}
+
+func (m *ModuleRegistry) serveSetSettings(e *core.RequestEvent, req setSettingsRequest) (SettingsInfo, error) {
+  node := m.findNode(req.Module)
+  if node == nil || moduleSettingsTarget(node.module) == nil {
+    return SettingsInfo{}, e.NotFoundError("Unknown module or module without settings.", nil)
+  }
+
+  var settingsErr *SettingsError
+  if err := m.SetSettings(req.Module, req.Settings); errors.As(err, &settingsErr) {
+    fieldErrs := validation.Errors{}
+    for _, field := range settingsErr.Fields {
+      fieldErrs[field.Field] = errors.New(field.Error)
+    }
+    return SettingsInfo{}, e.BadRequestError("Invalid settings.", fieldErrs)
+  } else if err != nil {
+    return SettingsInfo{}, err
+  }
+
+  return m.Settings(req.Module)
+}
+
+func (m *ModuleRegistry) serveResetSettings(e *core.RequestEvent, req settingsRequest) (SettingsInfo, error) {
+  node := m.findNode(req.Module)
+  if node == nil || moduleSettingsTarget(node.module) == nil {
+    return SettingsInfo{}, e.NotFoundError("Unknown module or module without settings.", nil)
+  }
+
+  if err := m.ResetSettings(req.Module); err != nil {
+    return SettingsInfo{}, err
+  }
+
+  return m.Settings(req.Module)
+}
@@ -0,0 +1,156 @@
+package pocketframework
+
+import (
+  "encoding/json"
+  "errors"
+  "reflect"
+  "testing"
+
+  validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type testSettings struct {
+  TrialDays int    `json:"trialDays" default:"14"`
+  Currency  string `json:"currency" default:"EUR"`
+}
+
+func (s *testSettings) Validate() error {
+  return validation.ValidateStruct(s, validation.Field(&s.TrialDays, validation.Max(30)))
+}
+
+// settingsModule is a test module with settings.
+type settingsModule struct {
+  testModule
+  ModuleSettings[testSettings]
+}
+
+func TestSetSettings(t *testing.T) {
+  tests := []struct {
+    name     string
+    data     string
+    settings testSettings
+    errs     []ConfigFieldError
+  }{
+    {
+      name:     "omitted fields are reset to the defaults",
+      data:     `{"trialDays": 7}`,
+      settings: testSettings{TrialDays: 7, Currency: "EUR"},
+    },
+    {
+      name:     "all fields",
+      data:     `{"trialDays": 30, "currency": "USD"}`,
+      settings: testSettings{TrialDays: 30, Currency: "USD"},
+    },
+    {
+      name: "validation",
+      data: `{"trialDays": 31}`,
+      errs: []ConfigFieldError{{Field: "trialDays", Error: "must be no greater than 30"}},
+    },
+    {
+      name: "unknown field",
+      data: `{"trialDay": 7}`,
+      errs: []ConfigFieldError{{Field: "-", Error: `invalid settings: json: unknown field "trialDay"`}},
+    },
+    {
+      name: "wrong type",
+      data: `{"trialDays": "7"}`,
+      errs: []ConfigFieldError{
+        {Field: "-", Error: "invalid settings: json: cannot unmarshal string into Go struct field testSettings.trialDays of type int"},
+      },
+    },
+  }
+
+  for _, tt := range tests {
+    t.Run(
+      tt.name, func(t *testing.T) {
+        module := &settingsModule{testModule: testModule{name: "billing"}}
+        registry, app := newTestRegistry(t, module)
+
+        err := registry.SetSettings("billing", json.RawMessage(tt.data))
+
+        var settingsErr *SettingsError
+        if errors.As(err, &settingsErr) {
+          if !reflect.DeepEqual(settingsErr.Fields, tt.errs) {
+            t.Errorf("SetSettings() errors = %+v, want %+v", settingsErr.Fields, tt.errs)
+          }
+          if settings := module.Settings(); settings != (testSettings{TrialDays: 14, Currency: "EUR"}) {
+            t.Errorf("invalid settings have been applied: %+v", settings)
+          }
+          return
+        }
+        if err != nil || tt.errs != nil {
+          t.Fatalf("SetSettings() = %v, want errors %+v", err, tt.errs)
+        }
+
+        if settings := module.Settings(); settings != tt.settings {
+          t.Errorf("Settings() = %+v, want %+v", settings, tt.settings)
+        }
+
+        // the persisted settings survive a reload
+        _ = module.applySettings("billing", testSettings{}, false)
+        if err := registry.ReloadSettings(); err != nil {
+          t.Fatalf("ReloadSettings() failed: %v", err)
+        }
+        if settings := module.Settings(); settings != tt.settings {
+          t.Errorf("Settings() after reloading = %+v, want %+v", settings, tt.settings)
+        }
+
+        collection, err := app.FindCollectionByNameOrId(SettingsCollection)
+        if err != nil || !collection.System {
+          t.Errorf("the settings are not stored in a system collection: %v", err)
+        }
+      },
+    )
+  }
+}
+
+func TestSettingsChanges(t *testing.T) {
+  module := &settingsModule{testModule: testModule{name: "billing"}}
+
+  changes := []testSettings{}
+  module.OnSettingsChange().BindFunc(
+    func(e *SettingsChangeEvent[testSettings]) error {
+      changes = append(changes, e.Settings)
+      return e.Next()
+    },
+  )
+
+  registry, app := newTestRegistry(t, module)
+
+  if err := registry.SetSettings("billing", json.RawMessage(`{"trialDays": 7}`)); err != nil {
+    t.Fatalf("SetSettings() failed: %v", err)
+  }
+  if info, _ := registry.Settings("billing"); !info.Persisted {
+    t.Errorf("the settings are not reported as persisted")
+  }
+
+  // changes made through the records API are applied without an explicit reload, invalid ones fall back to the defaults
+  record, err := app.FindFirstRecordByData(SettingsCollection, "module", "billing")
+  if err != nil {
+    t.Fatalf("the settings have not been persisted: %v", err)
+  }
+  record.Set("value", json.RawMessage(`{"trialDays": 21}`))
+  if err := app.Save(record); err != nil {
+    t.Fatalf("failed to update the settings record: %v", err)
+  }
+  record.Set("value", json.RawMessage(`{"trialDays": 99}`))
+  if err := app.Save(record); err != nil {
+    t.Fatalf("failed to update the settings record: %v", err)
+  }
+
+  if err := registry.ResetSettings("billing"); err != nil {
+    t.Fatalf("ResetSettings() failed: %v", err)
+  }
+  if info, _ := registry.Settings("billing"); info.Persisted {
+    t.Errorf("the settings are still reported as persisted after the reset")
+  }
+
+  expected := []testSettings{
+    {TrialDays: 7, Currency: "EUR"},
+    {TrialDays: 21, Currency: "EUR"},
+    {TrialDays: 14, Currency: "EUR"},
+  }
+  if !reflect.DeepEqual(changes, expected) {
+    t.Errorf("settings changes = %+v, want %+v", changes, expected)
+  }
+}
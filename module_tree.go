@@ -0,0 +1,314 @@
+package pocketframework
+
+import (
+  "fmt"
+  "reflect"
+  "slices"
+  "strconv"
+  "strings"
+)
+
+// moduleNode is a registered module together with its position in the module tree.
+type moduleNode struct {
+  module   Module
+  name     string
+  path     string
+  parent   *moduleNode
+  children []*moduleNode
+  state    ModuleState
+  hooks    *hookScope
+
+  // unnamed is true if the module has neither a name nor a prefix, so its name has been derived from its type
+  unnamed bool
+}
+
+// buildModuleTree resolves the identity of every module and returns the module tree together with all modules in
+// dependency order, see sortModuleNodes.
+func buildModuleTree(modules []Module) ([]*moduleNode, []*moduleNode, error) {
+  roots, err := newModuleNodes(modules, nil)
+  if err != nil {
+    return nil, nil, err
+  }
+
+  nodesByPath := map[string]*moduleNode{}
+  for _, root := range roots {
+    _ = root.walk(
+      func(node *moduleNode) error {
+        nodesByPath[node.path] = node
+        return nil
+      },
+    )
+  }
+
+  for _, root := range roots {
+    err := root.walk(
+      func(node *moduleNode) error {
+        for _, path := range moduleDependencies(node.module) {
+          dependency, ok := nodesByPath[path]
+          if !ok {
+            return fmt.Errorf("module %q depends on unknown module %q", node.path, path)
+          }
+          if dependency.unnamed {
+            return fmt.Errorf("module %q depends on module %q which has no name, implement ModuleWithName", node.path, path)
+          }
+          if node.isAncestorOf(dependency) {
+            return fmt.Errorf("module %q depends on its own child %q, but parents are initialized before their children", node.path, path)
+          }
+        }
+        return nil
+      },
+    )
+    if err != nil {
+      return nil, nil, err
+    }
+  }
+
+  if err := validatePrefixes(roots); err != nil {
+    return nil, nil, err
+  }
+
+  ordered, err := sortModuleNodes(roots, nodesByPath)
+  if err != nil {
+    return nil, nil, err
+  }
+
+  // every level of the tree is put into dependency order as well, so walking the tree lists dependencies first
+  positions := make(map[*moduleNode]int, len(ordered))
+  for i, node := range ordered {
+    positions[node] = i
+  }
+  byPosition := func(a *moduleNode, b *moduleNode) int {
+    return positions[a] - positions[b]
+  }
+  slices.SortStableFunc(roots, byPosition)
+  for _, node := range ordered {
+    slices.SortStableFunc(node.children, byPosition)
+  }
+
+  return roots, ordered, nil
+}
+
+func newModuleNodes(modules []Module, parent *moduleNode) ([]*moduleNode, error) {
+  nodes := make([]*moduleNode, 0, len(modules))
+  nodesByName := map[string]*moduleNode{}
+
+  for _, module := range modules {
+    node := &moduleNode{
+      module: module,
+      name:   moduleName(module),
+      parent: parent,
+      state:  ModuleStateRegistered,
+      hooks:  &hookScope{},
+    }
+
+    // modules without name and prefix, e.g. modules which only register hooks, are named after their type
+    if node.name == "" {
+      node.unnamed = true
+      node.name = unnamedModuleName(module)
+      for i := 2; nodesByName[node.name] != nil; i++ {
+        node.name = unnamedModuleName(module) + "-" + strconv.Itoa(i)
+      }
+    }
+
+    node.path = node.name
+    if parent != nil {
+      node.path = parent.path + "/" + node.name
+    }
+
+    if existing, ok := nodesByName[node.name]; ok {
+      return nil, fmt.Errorf("modules %T and %T share the path %q", existing.module, module, node.path)
+    }
+    nodesByName[node.name] = node
+
+    if moduleWithChildren, ok := module.(ModuleWithChildren); ok {
+      children, err := newModuleNodes(moduleWithChildren.Children(), node)
+      if err != nil {
+        return nil, err
+      }
+      node.children = children
+    }
+
+    nodes = append(nodes, node)
+  }
+
+  return nodes, nil
+}
+
+// validatePrefixes rejects malformed prefixes and prefixes which overlap with the prefix of another module. The
+// prefix of a child is compared with its full path, so "/billing" + "/invoices" overlaps with "/billing/invoices".
+// Modules without a prefix share the routes of their parent and are not checked for overlaps.
+func validatePrefixes(roots []*moduleNode) error {
+  nodes := []*moduleNode{}
+  fullPrefixes := map[*moduleNode]string{}
+
+  for _, root := range roots {
+    err := root.walk(
+      func(node *moduleNode) error {
+        prefix := node.module.Prefix()
+        if prefix != "" && (!strings.HasPrefix(prefix, "/") || strings.HasSuffix(prefix, "/") || strings.Contains(prefix, "//")) {
+          return fmt.Errorf("module %q has the malformed prefix %q, it must start with a single slash and must not contain empty segments or end with a slash", node.path, prefix)
+        }
+
+        fullPrefixes[node] = fullPrefixes[node.parent] + prefix
+        if prefix != "" {
+          nodes = append(nodes, node)
+        }
+        return nil
+      },
+    )
+    if err != nil {
+      return err
+    }
+  }
+
+  for i, a := range nodes {
+    for _, b := range nodes[i+1:] {
+      if a.isAncestorOf(b) || b.isAncestorOf(a) {
+        continue
+      }
+
+      if fullPrefixes[a] == fullPrefixes[b] {
+        return fmt.Errorf("modules %q and %q both use the prefix %q", a.path, b.path, fullPrefixes[a])
+      }
+      if prefixesOverlap(fullPrefixes[a], fullPrefixes[b]) {
+        return fmt.Errorf("the prefix %q of module %q overlaps with the prefix %q of module %q", fullPrefixes[a], a.path, fullPrefixes[b], b.path)
+      }
+    }
+  }
+
+  return nil
+}
+
+// prefixesOverlap reports whether one prefix contains the other, treating path parameters such as {id} as wildcards.
+func prefixesOverlap(a string, b string) bool {
+  aSegments := strings.Split(strings.Trim(a, "/"), "/")
+  bSegments := strings.Split(strings.Trim(b, "/"), "/")
+
+  for i := 0; i < min(len(aSegments), len(bSegments)); i++ {
+    if aSegments[i] != bSegments[i] && !isPathParam(aSegments[i]) && !isPathParam(bSegments[i]) {
+      return false
+    }
+  }
+
+  return true
+}
+
+func isPathParam(segment string) bool {
+  return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// sortModuleNodes orders all modules of the tree topologically, keeping the registration order where possible. Every
+// module comes after its dependencies and after its parent.
+func sortModuleNodes(roots []*moduleNode, nodesByPath map[string]*moduleNode) ([]*moduleNode, error) {
+  const (
+    unvisited = iota
+    visiting
+    visited
+  )
+
+  states := map[*moduleNode]int{}
+  sorted := []*moduleNode{}
+  stack := []*moduleNode{}
+
+  var visit func(node *moduleNode) error
+  visit = func(node *moduleNode) error {
+    switch states[node] {
+    case visited:
+      return nil
+    case visiting:
+      chain := []string{}
+      for i := len(stack) - 1; i >= 0; i-- {
+        chain = append([]string{stack[i].path}, chain...)
+        if stack[i] == node {
+          break
+        }
+      }
+      chain = append(chain, node.path)
+      return fmt.Errorf("module dependency cycle: %s", strings.Join(chain, " -> "))
+    }
+
+    states[node] = visiting
+    stack = append(stack, node)
+
+    if node.parent != nil {
+      if err := visit(node.parent); err != nil {
+        return err
+      }
+    }
+    for _, path := range moduleDependencies(node.module) {
+      if err := visit(nodesByPath[path]); err != nil {
+        return err
+      }
+    }
+
+    stack = stack[:len(stack)-1]
+    states[node] = visited
+    sorted = append(sorted, node)
+
+    return nil
+  }
+
+  for _, root := range roots {
+    if err := root.walk(visit); err != nil {
+      return nil, err
+    }
+  }
+
+  return sorted, nil
+}
+
+func (n *moduleNode) isAncestorOf(other *moduleNode) bool {
+  for parent := other.parent; parent != nil; parent = parent.parent {
+    if parent == n {
+      return true
+    }
+  }
+
+  return false
+}
+
+// walk calls fn for this node and all of its descendants, parents before children.
+func (n *moduleNode) walk(fn func(node *moduleNode) error) error {
+  if err := fn(n); err != nil {
+    return err
+  }
+
+  for _, child := range n.children {
+    if err := child.walk(fn); err != nil {
+      return err
+    }
+  }
+
+  return nil
+}
+
+func moduleName(module Module) string {
+  if moduleWithName, ok := module.(ModuleWithName); ok {
+    return moduleWithName.Name()
+  }
+
+  return strings.ReplaceAll(strings.Trim(module.Prefix(), "/"), "/", "-")
+}
+
+// unnamedModuleName derives the name of a module without name and prefix from its type, e.g. "audithooks" for
+// *audit.AuditHooks.
+func unnamedModuleName(module Module) string {
+  typ := reflect.TypeOf(module)
+  for typ.Kind() == reflect.Pointer {
+    typ = typ.Elem()
+  }
+
+  if typ.Name() == "" {
+    return "module"
+  }
+
+  return strings.ToLower(typ.Name())
+}
+
+func moduleDependencies(module Module) []string {
+  if moduleWithDependencies, ok := module.(ModuleWithDependencies); ok {
+    return moduleWithDependencies.DependsOn()
+  }
+
+  return nil
+}
@@ -0,0 +1,276 @@
+package pocketframework
+
+import (
+  "strings"
+  "testing"
+)
+
+// testModule is a configurable module for the tests of this package.
+type testModule struct {
+  name      string
+  prefix    string
+  dependsOn []string
+  children  []Module
+  routes    func(groups RouterGroups) error
+}
+
+func (t *testModule) Name() string {
+  return t.name
+}
+
+func (t *testModule) Prefix() string {
+  return t.prefix
+}
+
+func (t *testModule) DependsOn() []string {
+  return t.dependsOn
+}
+
+func (t *testModule) Children() []Module {
+  return t.children
+}
+
+func (t *testModule) RegisterHooks(app ModuleAppHooks) error {
+  return nil
+}
+
+func (t *testModule) RegisterRoutes(groups RouterGroups) error {
+  if t.routes == nil {
+    return nil
+  }
+
+  return t.routes(groups)
+}
+
+// hooksOnlyModule is a module without name and prefix.
+type hooksOnlyModule struct{}
+
+func (h *hooksOnlyModule) Prefix() string {
+  return ""
+}
+
+func (h *hooksOnlyModule) RegisterHooks(app ModuleAppHooks) error {
+  return nil
+}
+
+func (h *hooksOnlyModule) RegisterRoutes(groups RouterGroups) error {
+  return nil
+}
+
+func TestBuildModuleTreeOrder(t *testing.T) {
+  tests := []struct {
+    name    string
+    modules []Module
+    order   []string
+  }{
+    {
+      name: "registration order without dependencies",
+      modules: []Module{
+        &testModule{name: "a"},
+        &testModule{name: "b"},
+      },
+      order: []string{"a", "b"},
+    },
+    {
+      name: "dependencies first",
+      modules: []Module{
+        &testModule{name: "a", dependsOn: []string{"b"}},
+        &testModule{name: "b"},
+      },
+      order: []string{"b", "a"},
+    },
+    {
+      name: "parents before children",
+      modules: []Module{
+        &testModule{name: "a", children: []Module{&testModule{name: "x"}}},
+      },
+      order: []string{"a", "a/x"},
+    },
+    {
+      name: "children depending on modules of other subtrees",
+      modules: []Module{
+        &testModule{name: "a", children: []Module{&testModule{name: "x", dependsOn: []string{"b"}}}},
+        &testModule{name: "b", children: []Module{&testModule{name: "y", dependsOn: []string{"a"}}}},
+      },
+      order: []string{"a", "b", "a/x", "b/y"},
+    },
+    {
+      name: "child depending on a sibling",
+      modules: []Module{
+        &testModule{
+          name: "a",
+          children: []Module{
+            &testModule{name: "x", dependsOn: []string{"a/y"}},
+            &testModule{name: "y"},
+          },
+        },
+      },
+      order: []string{"a", "a/y", "a/x"},
+    },
+    {
+      name: "modules without name and prefix",
+      modules: []Module{
+        &hooksOnlyModule{},
+        &testModule{name: "a", children: []Module{&hooksOnlyModule{}}},
+        &hooksOnlyModule{},
+      },
+      order: []string{"hooksonlymodule", "a", "a/hooksonlymodule", "hooksonlymodule-2"},
+    },
+  }
+
+  for _, test := range tests {
+    t.Run(
+      test.name, func(t *testing.T) {
+        _, ordered, err := buildModuleTree(test.modules)
+        if err != nil {
+          t.Fatalf("unexpected error: %v", err)
+        }
+
+        paths := make([]string, 0, len(ordered))
+        for _, node := range ordered {
+          paths = append(paths, node.path)
+        }
+
+        if strings.Join(paths, ",") != strings.Join(test.order, ",") {
+          t.Fatalf("expected order %v, got %v", test.order, paths)
+        }
+      },
+    )
+  }
+}
+
+func TestBuildModuleTreeErrors(t *testing.T) {
+  tests := []struct {
+    name    string
+    modules []Module
+    err     string
+  }{
+    {
+      name: "unknown dependency",
+      modules: []Module{
+        &testModule{name: "a", dependsOn: []string{"missing"}},
+      },
+      err: `module "a" depends on unknown module "missing"`,
+    },
+    {
+      name: "direct cycle",
+      modules: []Module{
+        &testModule{name: "a", dependsOn: []string{"b"}},
+        &testModule{name: "b", dependsOn: []string{"a"}},
+      },
+      err: "module dependency cycle: a -> b -> a",
+    },
+    {
+      name: "cycle across levels",
+      modules: []Module{
+        &testModule{name: "a", children: []Module{&testModule{name: "x", dependsOn: []string{"b/y"}}}},
+        &testModule{name: "b", children: []Module{&testModule{name: "y", dependsOn: []string{"a/x"}}}},
+      },
+      err: "module dependency cycle: a/x -> b/y -> a/x",
+    },
+    {
+      name: "parent depending on its child",
+      modules: []Module{
+        &testModule{name: "a", dependsOn: []string{"a/x"}, children: []Module{&testModule{name: "x"}}},
+      },
+      err: `module "a" depends on its own child "a/x"`,
+    },
+    {
+      name: "dependency on a module without name",
+      modules: []Module{
+        &hooksOnlyModule{},
+        &testModule{name: "a", dependsOn: []string{"hooksonlymodule"}},
+      },
+      err: `module "a" depends on module "hooksonlymodule" which has no name`,
+    },
+    {
+      name: "duplicate names",
+      modules: []Module{
+        &testModule{name: "a"},
+        &testModule{name: "a"},
+      },
+      err: `share the path "a"`,
+    },
+  }
+
+  for _, test := range tests {
+    t.Run(
+      test.name, func(t *testing.T) {
+        _, _, err := buildModuleTree(test.modules)
+        if err == nil {
+          t.Fatal("expected an error")
+        }
+
+        if !strings.Contains(err.Error(), test.err) {
+          t.Fatalf("expected error containing %q, got %q", test.err, err)
+        }
+      },
+    )
+  }
+}
+
+func TestValidatePrefixes(t *testing.T) {
+  tests := []struct {
+    name    string
+    modules []Module
+    err     string
+  }{
+    {
+      name: "distinct prefixes",
+      modules: []Module{
+        &testModule{name: "billing", prefix: "/billing", children: []Module{&testModule{name: "invoices", prefix: "/invoices"}}},
+        &testModule{name: "crm", prefix: "/crm"},
+        &testModule{name: "shared"},
+      },
+    },
+    {
+      name:    "missing leading slash",
+      modules: []Module{&testModule{name: "billing", prefix: "billing"}},
+      err:     `module "billing" has the malformed prefix "billing"`,
+    },
+    {
+      name:    "trailing slash",
+      modules: []Module{&testModule{name: "billing", prefix: "/billing/"}},
+      err:     `module "billing" has the malformed prefix "/billing/"`,
+    },
+    {
+      name: "duplicate prefix",
+      modules: []Module{
+        &testModule{name: "a", prefix: "/billing"},
+        &testModule{name: "b", prefix: "/billing"},
+      },
+      err: `modules "a" and "b" both use the prefix "/billing"`,
+    },
+    {
+      name: "overlap with a nested prefix",
+      modules: []Module{
+        &testModule{name: "billing", prefix: "/billing", children: []Module{&testModule{name: "invoices", prefix: "/invoices"}}},
+        &testModule{name: "invoices", prefix: "/billing/invoices/archive"},
+      },
+      err: `overlaps with the prefix "/billing/invoices/archive" of module "invoices"`,
+    },
+    {
+      name: "overlap through a path parameter",
+      modules: []Module{
+        &testModule{name: "a", prefix: "/orgs/{org}/billing"},
+        &testModule{name: "b", prefix: "/orgs/acme"},
+      },
+      err: "overlaps with",
+    },
+  }
+
+  for _, test := range tests {
+    t.Run(
+      test.name, func(t *testing.T) {
+        _, _, err := buildModuleTree(test.modules)
+
+        if test.err == "" && err != nil {
+          t.Fatalf("unexpected error: %v", err)
+        }
+        if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+          t.Fatalf("expected error containing %q, got %v", test.err, err)
+        }
+      },
+    )
+  }
+}
@@ -0,0 +1,439 @@
+package pocketframework
+
+import (
+  "encoding"
+  "encoding/json"
+  "net/http"
+  "os"
+  "reflect"
+  "regexp"
+  "strconv"
+  "strings"
+  "time"
+
+  "github.com/pocketbase/pocketbase/core"
+  "github.com/pocketbase/pocketbase/tools/types"
+)
+
+const (
+  SecuritySchemeAuth      = "authToken"
+  SecuritySchemeSuperuser = "superuserToken"
+)
+
+// OpenAPIConfig configures the generated OpenAPI document.
+type OpenAPIConfig struct {
+  // Path is the path the document is served at, e.g. "/api/openapi.json". The document is not served if empty.
+  Path string
+
+  Title       string
+  Version     string
+  Description string
+  Servers     []string
+}
+
+// WithOpenAPI configures the generated OpenAPI document and where it is served.
+func WithOpenAPI(config OpenAPIConfig) RegistryOption {
+  return func(m *ModuleRegistry) {
+    m.openAPI = config
+  }
+}
+
+type OpenAPIDocument struct {
+  OpenAPI    string                                  `json:"openapi"`
+  Info       OpenAPIInfo                             `json:"info"`
+  Servers    []OpenAPIServer                         `json:"servers,omitempty"`
+  Paths      map[string]map[string]*OpenAPIOperation `json:"paths"`
+  Components OpenAPIComponents                       `json:"components"`
+}
+
+type OpenAPIInfo struct {
+  Title       string `json:"title"`
+  Version     string `json:"version"`
+  Description string `json:"description,omitempty"`
+}
+
+type OpenAPIServer struct {
+  Url string `json:"url"`
+}
+
+type OpenAPIOperation struct {
+  OperationId string                     `json:"operationId,omitempty"`
+  Summary     string                     `json:"summary,omitempty"`
+  Description string                     `json:"description,omitempty"`
+  Tags        []string                   `json:"tags,omitempty"`
+  Deprecated  bool                       `json:"deprecated,omitempty"`
+  Parameters  []OpenAPIParameter         `json:"parameters,omitempty"`
+  RequestBody *OpenAPIRequestBody        `json:"requestBody,omitempty"`
+  Responses   map[string]OpenAPIResponse `json:"responses"`
+  Security    []map[string][]string      `json:"security,omitempty"`
+}
+
+type OpenAPIParameter struct {
+  Name     string      `json:"name"`
+  In       string      `json:"in"`
+  Required bool        `json:"required,omitempty"`
+  Schema   *JSONSchema `json:"schema,omitempty"`
+}
+
+type OpenAPIRequestBody struct {
+  Required bool                        `json:"required,omitempty"`
+  Content  map[string]OpenAPIMediaType `json:"content"`
+}
+
+type OpenAPIResponse struct {
+  Description string                      `json:"description"`
+  Content     map[string]OpenAPIMediaType `json:"content,omitempty"`
+}
+
+type OpenAPIMediaType struct {
+  Schema *JSONSchema `json:"schema,omitempty"`
+}
+
+type OpenAPIComponents struct {
+  Schemas         map[string]*JSONSchema           `json:"schemas,omitempty"`
+  SecuritySchemes map[string]OpenAPISecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type OpenAPISecurityScheme struct {
+  Type        string `json:"type"`
+  Description string `json:"description,omitempty"`
+  Name        string `json:"name,omitempty"`
+  In          string `json:"in,omitempty"`
+}
+
+// JSONSchema is the subset of JSON Schema used by the generated OpenAPI document.
+type JSONSchema struct {
+  Ref                  string                 `json:"$ref,omitempty"`
+  Type                 any                    `json:"type,omitempty"`
+  Format               string                 `json:"format,omitempty"`
+  Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+  Required             []string               `json:"required,omitempty"`
+  Items                *JSONSchema            `json:"items,omitempty"`
+  AdditionalProperties *JSONSchema            `json:"additionalProperties,omitempty"`
+}
+
+var pathParamRegex = regexp.MustCompile(`\{([^}.]+)(\.\.\.)?\}`)
+
+// OpenAPI generates an OpenAPI 3.1 document of all routes mounted by the modules.
+func (m *ModuleRegistry) OpenAPI() (*OpenAPIDocument, error) {
+  routes, err := m.Routes()
+  if err != nil {
+    return nil, err
+  }
+
+  doc := &OpenAPIDocument{
+    OpenAPI: "3.1.0",
+    Info: OpenAPIInfo{
+      Title:       m.openAPI.Title,
+      Version:     m.openAPI.Version,
+      Description: m.openAPI.Description,
+    },
+    Paths: map[string]map[string]*OpenAPIOperation{},
+    Components: OpenAPIComponents{
+      Schemas: map[string]*JSONSchema{},
+      SecuritySchemes: map[string]OpenAPISecurityScheme{
+        SecuritySchemeAuth: {
+          Type:        "apiKey",
+          In:          "header",
+          Name:        "Authorization",
+          Description: "Auth token of an auth record.",
+        },
+        SecuritySchemeSuperuser: {
+          Type:        "apiKey",
+          In:          "header",
+          Name:        "Authorization",
+          Description: "Auth token of a superuser.",
+        },
+      },
+    },
+  }
+  if doc.Info.Title == "" {
+    doc.Info.Title = "API"
+  }
+  if doc.Info.Version == "" {
+    doc.Info.Version = "1.0.0"
+  }
+  for _, server := range m.openAPI.Servers {
+    doc.Servers = append(doc.Servers, OpenAPIServer{Url: server})
+  }
+
+  security := map[string]string{}
+  for _, group := range m.groupDefinitions() {
+    security[group.Name] = group.Security
+  }
+
+  schemas := newSchemaGenerator(doc.Components.Schemas)
+  for _, route := range routes {
+    path := strings.TrimSuffix(pathParamRegex.ReplaceAllString(route.Path, "{$1}"), "{$}")
+
+    methods := []string{strings.ToLower(route.Method)}
+    if route.Method == MethodAny {
+      methods = []string{"get", "put", "post", "delete", "patch"}
+    }
+
+    for _, method := range methods {
+      if doc.Paths[path] == nil {
+        doc.Paths[path] = map[string]*OpenAPIOperation{}
+      }
+      doc.Paths[path][method] = newOpenAPIOperation(route, path, method, security[route.Group], schemas)
+    }
+  }
+
+  return doc, nil
+}
+
+// ExportOpenAPI writes the OpenAPI document to the given file.
+func (m *ModuleRegistry) ExportOpenAPI(path string) error {
+  doc, err := m.OpenAPI()
+  if err != nil {
+    return err
+  }
+
+  data, err := json.MarshalIndent(doc, "", "  ")
+  if err != nil {
+    return err
+  }
+
+  return os.WriteFile(path, data, 0o644)
+}
+
+func (m *ModuleRegistry) serveOpenAPI(e *core.RequestEvent) error {
+  doc, err := m.OpenAPI()
+  if err != nil {
+    return e.InternalServerError("Failed to generate the OpenAPI document.", err)
+  }
+
+  return e.JSON(http.StatusOK, doc)
+}
+
+func newOpenAPIOperation(route RouteInfo, path string, method string, security string, schemas *schemaGenerator) *OpenAPIOperation {
+  operation := &OpenAPIOperation{
+    Responses: map[string]OpenAPIResponse{
+      "default": {
+        Description: "Error",
+        Content: map[string]OpenAPIMediaType{
+          "application/json": {Schema: schemas.schemaOf(reflect.TypeFor[apiErrorBody]())},
+        },
+      },
+    },
+  }
+
+  for _, match := range pathParamRegex.FindAllStringSubmatch(path, -1) {
+    operation.Parameters = append(
+      operation.Parameters, OpenAPIParameter{
+        Name:     match[1],
+        In:       "path",
+        Required: true,
+        Schema:   &JSONSchema{Type: "string"},
+      },
+    )
+  }
+
+  if security != "" {
+    operation.Security = []map[string][]string{{security: {}}}
+  }
+
+  status := http.StatusOK
+  response := OpenAPIResponse{Description: http.StatusText(status)}
+
+  if doc := route.Doc; doc != nil {
+    operation.OperationId = doc.OperationId
+    operation.Summary = doc.Summary
+    operation.Description = doc.Description
+    operation.Tags = doc.Tags
+    operation.Deprecated = doc.Deprecated
+
+    if doc.Request != nil {
+      operation.Parameters = append(operation.Parameters, queryParameters(reflect.TypeOf(doc.Request), schemas)...)
+    }
+
+    if doc.Request != nil && method != "get" && method != "delete" {
+      operation.RequestBody = &OpenAPIRequestBody{
+        Required: true,
+        Content: map[string]OpenAPIMediaType{
+          "application/json": {Schema: schemas.schemaOf(reflect.TypeOf(doc.Request))},
+        },
+      }
+    }
+
+    // typed handlers respond to NoContent with 204 and an empty body
+    _, noContent := doc.Response.(NoContent)
+    if noContent {
+      status = http.StatusNoContent
+    }
+    if doc.ResponseStatus != 0 {
+      status = doc.ResponseStatus
+    }
+    response.Description = http.StatusText(status)
+
+    if doc.Response != nil && !noContent {
+      response.Content = map[string]OpenAPIMediaType{
+        "application/json": {Schema: schemas.schemaOf(reflect.TypeOf(doc.Response))},
+      }
+    }
+  }
+
+  if len(operation.Tags) == 0 && route.Module != "" {
+    operation.Tags = append(operation.Tags, route.Module)
+  }
+
+  operation.Responses[strconv.Itoa(status)] = response
+
+  return operation
+}
+
+// queryParameters returns the parameters of all request fields with a "query" tag, see Handle.
+func queryParameters(t reflect.Type, schemas *schemaGenerator) []OpenAPIParameter {
+  for t.Kind() == reflect.Pointer {
+    t = t.Elem()
+  }
+  if t.Kind() != reflect.Struct {
+    return nil
+  }
+
+  parameters := []OpenAPIParameter{}
+  for _, field := range reflect.VisibleFields(t) {
+    if name := field.Tag.Get("query"); name != "" && field.IsExported() {
+      parameters = append(parameters, OpenAPIParameter{Name: name, In: "query", Schema: schemas.schemaOf(field.Type)})
+    }
+  }
+
+  return parameters
+}
+
+// apiErrorBody documents the error responses of pocketbase.
+type apiErrorBody struct {
+  Status  int            `json:"status"`
+  Message string         `json:"message"`
+  Data    map[string]any `json:"data"`
+}
+
+// schemaGenerator creates JSON schemas from go types. Named structs are added to the components and referenced.
+type schemaGenerator struct {
+  schemas map[string]*JSONSchema
+  names   map[reflect.Type]string
+}
+
+func newSchemaGenerator(schemas map[string]*JSONSchema) *schemaGenerator {
+  return &schemaGenerator{
+    schemas: schemas,
+    names:   map[reflect.Type]string{},
+  }
+}
+
+var (
+  timeType            = reflect.TypeFor[time.Time]()
+  dateTimeType        = reflect.TypeFor[types.DateTime]()
+  textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+  jsonMarshalerType   = reflect.TypeFor[json.Marshaler]()
+  schemaNameCharRegex = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+  typeQualifierRegex  = regexp.MustCompile(`[A-Za-z0-9_./-]+\.`)
+)
+
+func (g *schemaGenerator) schemaOf(t reflect.Type) *JSONSchema {
+  nullable := false
+  for t.Kind() == reflect.Pointer {
+    t = t.Elem()
+    nullable = true
+  }
+
+  schema := g.baseSchemaOf(t)
+  if nullable && schema.Ref == "" {
+    if typ, ok := schema.Type.(string); ok {
+      schema.Type = []string{typ, "null"}
+    }
+  }
+
+  return schema
+}
+
+func (g *schemaGenerator) baseSchemaOf(t reflect.Type) *JSONSchema {
+  switch {
+  case t == timeType, t == dateTimeType:
+    return &JSONSchema{Type: "string", Format: "date-time"}
+  case t.Kind() == reflect.String:
+  case implements(t, jsonMarshalerType):
+    return &JSONSchema{}
+  case implements(t, textMarshalerType):
+    return &JSONSchema{Type: "string"}
+  }
+
+  switch t.Kind() {
+  case reflect.Bool:
+    return &JSONSchema{Type: "boolean"}
+  case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+    reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+    return &JSONSchema{Type: "integer"}
+  case reflect.Float32, reflect.Float64:
+    return &JSONSchema{Type: "number"}
+  case reflect.String:
+    return &JSONSchema{Type: "string"}
+  case reflect.Slice, reflect.Array:
+    if t.Elem().Kind() == reflect.Uint8 {
+      return &JSONSchema{Type: "string", Format: "byte"}
+    }
+    return &JSONSchema{Type: "array", Items: g.schemaOf(t.Elem())}
+  case reflect.Map:
+    return &JSONSchema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+  case reflect.Struct:
+    if t.Name() == "" {
+      return g.structSchema(t)
+    }
+    return &JSONSchema{Ref: "#/components/schemas/" + g.register(t)}
+  default:
+    return &JSONSchema{}
+  }
+}
+
+// register adds the schema of a named struct to the components and returns its name.
+func (g *schemaGenerator) register(t reflect.Type) string {
+  if name, ok := g.names[t]; ok {
+    return name
+  }
+
+  name := strings.Trim(schemaNameCharRegex.ReplaceAllString(typeQualifierRegex.ReplaceAllString(t.Name(), ""), "_"), "_")
+  if _, exists := g.schemas[name]; exists {
+    name = schemaNameCharRegex.ReplaceAllString(t.PkgPath()+"_"+t.Name(), "_")
+  }
+
+  g.names[t] = name
+  g.schemas[name] = &JSONSchema{}
+  *g.schemas[name] = *g.structSchema(t)
+
+  return name
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) *JSONSchema {
+  schema := &JSONSchema{
+    Type:       "object",
+    Properties: map[string]*JSONSchema{},
+  }
+
+  for _, field := range reflect.VisibleFields(t) {
+    tag := field.Tag.Get("json")
+    name, options, _ := strings.Cut(tag, ",")
+    if !field.IsExported() || tag == "-" || (field.Anonymous && name == "") {
+      continue
+    }
+
+    // fields bound from the path or the query string are documented as parameters, not as part of the body
+    if field.Tag.Get("path") != "" || field.Tag.Get("query") != "" {
+      continue
+    }
+
+    if name == "" {
+      name = field.Name
+    }
+
+    schema.Properties[name] = g.schemaOf(field.Type)
+    if !strings.Contains(options, "omitempty") && !strings.Contains(options, "omitzero") && field.Type.Kind() != reflect.Pointer {
+      schema.Required = append(schema.Required, name)
+    }
+  }
+
+  return schema
+}
+
+func implements(t reflect.Type, iface reflect.Type) bool {
+  return t.Implements(iface) || reflect.PointerTo(t).Implements(iface)
+}
@@ -0,0 +1,202 @@
+package pocketframework
+
+import (
+  "net/http"
+  "reflect"
+  "testing"
+
+  "github.com/pocketbase/pocketbase/core"
+)
+
+type updateInvoiceRequest struct {
+  Id     string   `json:"id" path:"id"`
+  Notify bool     `json:"notify" query:"notify"`
+  Amount int      `json:"amount"`
+  Note   *string  `json:"note"`
+  Tags   []string `json:"tags,omitempty"`
+}
+
+type invoiceResponse struct {
+  Id     string `json:"id"`
+  Amount int    `json:"amount"`
+}
+
+func newOpenAPITestRegistry(t *testing.T) *ModuleRegistry {
+  handler := func(e *core.RequestEvent) error {
+    return nil
+  }
+
+  m := NewModuleRegistry(nil, "/api", WithOpenAPI(OpenAPIConfig{Title: "Billing", Servers: []string{"https://example.com"}}))
+  m.Register(
+    &testModule{
+      name:   "billing",
+      prefix: "/billing",
+      routes: func(groups RouterGroups) error {
+        groups.Authenticated.PATCH("/invoices/{id}", handler).Doc(
+          RouteDoc{
+            OperationId: "updateInvoice",
+            Request:     updateInvoiceRequest{},
+            Response:    invoiceResponse{},
+          },
+        )
+        groups.Admin.DELETE("/invoices/{id}", handler).Doc(RouteDoc{Response: NoContent{}})
+        groups.Public.GET("/status", handler)
+        return nil
+      },
+    },
+  )
+
+  var err error
+  if m.roots, m.ordered, err = buildModuleTree(m.modules); err != nil {
+    t.Fatal(err)
+  }
+
+  return m
+}
+
+func TestOpenAPI(t *testing.T) {
+  doc, err := newOpenAPITestRegistry(t).OpenAPI()
+  if err != nil {
+    t.Fatalf("unexpected error: %v", err)
+  }
+
+  if doc.Info.Title != "Billing" || doc.Info.Version != "1.0.0" {
+    t.Errorf("unexpected info %+v", doc.Info)
+  }
+  if !reflect.DeepEqual(doc.Servers, []OpenAPIServer{{Url: "https://example.com"}}) {
+    t.Errorf("unexpected servers %+v", doc.Servers)
+  }
+
+  t.Run(
+    "request and response", func(t *testing.T) {
+      operation := doc.Paths["/api/billing/invoices/{id}"]["patch"]
+      if operation == nil {
+        t.Fatalf("expected the operation PATCH /api/billing/invoices/{id}, got paths %v", doc.Paths)
+      }
+
+      if operation.OperationId != "updateInvoice" {
+        t.Errorf("expected the operation id %q, got %q", "updateInvoice", operation.OperationId)
+      }
+      if !reflect.DeepEqual(operation.Tags, []string{"billing"}) {
+        t.Errorf("expected the module as tag, got %v", operation.Tags)
+      }
+      if !reflect.DeepEqual(operation.Security, []map[string][]string{{SecuritySchemeAuth: {}}}) {
+        t.Errorf("expected the security of the authenticated group, got %v", operation.Security)
+      }
+
+      expectedParameters := []OpenAPIParameter{
+        {Name: "id", In: "path", Required: true, Schema: &JSONSchema{Type: "string"}},
+        {Name: "notify", In: "query", Schema: &JSONSchema{Type: "boolean"}},
+      }
+      if !reflect.DeepEqual(operation.Parameters, expectedParameters) {
+        t.Errorf("expected the parameters %+v, got %+v", expectedParameters, operation.Parameters)
+      }
+
+      if operation.RequestBody == nil {
+        t.Fatal("expected a request body")
+      }
+      if ref := operation.RequestBody.Content["application/json"].Schema.Ref; ref != "#/components/schemas/updateInvoiceRequest" {
+        t.Errorf("unexpected request schema %q", ref)
+      }
+
+      expectedRequest := &JSONSchema{
+        Type: "object",
+        Properties: map[string]*JSONSchema{
+          "amount": {Type: "integer"},
+          "note":   {Type: []string{"string", "null"}},
+          "tags":   {Type: "array", Items: &JSONSchema{Type: "string"}},
+        },
+        Required: []string{"amount"},
+      }
+      if actual := doc.Components.Schemas["updateInvoiceRequest"]; !reflect.DeepEqual(actual, expectedRequest) {
+        t.Errorf("expected the request schema %+v without path and query fields, got %+v", expectedRequest, actual)
+      }
+
+      response, ok := operation.Responses["200"]
+      if !ok {
+        t.Fatalf("expected a 200 response, got %v", operation.Responses)
+      }
+      if ref := response.Content["application/json"].Schema.Ref; ref != "#/components/schemas/invoiceResponse" {
+        t.Errorf("unexpected response schema %q", ref)
+      }
+      if _, ok := operation.Responses["default"]; !ok {
+        t.Errorf("expected a default error response")
+      }
+    },
+  )
+
+  t.Run(
+    "no content", func(t *testing.T) {
+      operation := doc.Paths["/api/billing/invoices/{id}"]["delete"]
+      if operation == nil {
+        t.Fatal("expected the operation DELETE /api/billing/invoices/{id}")
+      }
+
+      response, ok := operation.Responses["204"]
+      if !ok {
+        t.Fatalf("expected a 204 response, got %v", operation.Responses)
+      }
+      if response.Description != http.StatusText(http.StatusNoContent) || response.Content != nil {
+        t.Errorf("expected an empty 204 response, got %+v", response)
+      }
+      if operation.RequestBody != nil {
+        t.Errorf("expected no request body")
+      }
+      if !reflect.DeepEqual(operation.Security, []map[string][]string{{SecuritySchemeSuperuser: {}}}) {
+        t.Errorf("expected the security of the admin group, got %v", operation.Security)
+      }
+    },
+  )
+
+  t.Run(
+    "undocumented route", func(t *testing.T) {
+      operation := doc.Paths["/api/billing/status"]["get"]
+      if operation == nil {
+        t.Fatal("expected the operation GET /api/billing/status")
+      }
+
+      if operation.Security != nil || operation.Parameters != nil || operation.RequestBody != nil {
+        t.Errorf("expected a public operation without parameters, got %+v", operation)
+      }
+      if _, ok := operation.Responses["200"]; !ok {
+        t.Errorf("expected a 200 response, got %v", operation.Responses)
+      }
+    },
+  )
+}
+
+func TestSchemaOf(t *testing.T) {
+  type nested struct {
+    Name string `json:"name"`
+  }
+  type payload struct {
+    Nested   nested            `json:"nested"`
+    Labels   map[string]string `json:"labels,omitempty"`
+    Data     []byte            `json:"data"`
+    internal string
+    Skipped  string `json:"-"`
+  }
+
+  schemas := map[string]*JSONSchema{}
+  schema := newSchemaGenerator(schemas).schemaOf(reflect.TypeFor[*payload]())
+
+  if schema.Ref != "#/components/schemas/payload" {
+    t.Fatalf("expected a reference to the payload schema, got %+v", schema)
+  }
+
+  expected := &JSONSchema{
+    Type: "object",
+    Properties: map[string]*JSONSchema{
+      "nested": {Ref: "#/components/schemas/nested"},
+      "labels": {Type: "object", AdditionalProperties: &JSONSchema{Type: "string"}},
+      "data":   {Type: "string", Format: "byte"},
+    },
+    Required: []string{"nested", "data"},
+  }
+  if !reflect.DeepEqual(schemas["payload"], expected) {
+    t.Errorf("expected %+v, got %+v", expected, schemas["payload"])
+  }
+  if _, ok := schemas["nested"]; !ok {
+    t.Errorf("expected the nested schema to be registered, got %v", schemas)
+  }
+}
@@ -0,0 +1,215 @@
+// Package pocketframeworktest boots a temporary pocketbase app with a ModuleRegistry for testing modules.
+//
+//  app := pocketframeworktest.New(t, []pocketframework.Module{invoices.NewModule()})
+//  app.Request(http.MethodGet, "/api/invoices").AsSuperuser().Do().AssertStatus(http.StatusOK)
+package pocketframeworktest
+
+import (
+  "net/http"
+  "reflect"
+  "strings"
+  "sync"
+  "testing"
+
+  "github.com/leon-marzahn/pocketframework"
+  "github.com/pocketbase/pocketbase/apis"
+  "github.com/pocketbase/pocketbase/core"
+  _ "github.com/pocketbase/pocketbase/migrations"
+)
+
+// DefaultAPIPrefix is the API prefix of the registry unless configured otherwise.
+const DefaultAPIPrefix = "/api"
+
+// DefaultSuperuserEmail is the email of the superuser created by TestApp.Superuser.
+const DefaultSuperuserEmail = "superuser@example.com"
+
+// Option configures a TestApp.
+type Option func(c *config)
+
+type config struct {
+  apiPrefix       string
+  registryOptions []pocketframework.RegistryOption
+}
+
+// WithAPIPrefix sets the API prefix of the registry. Defaults to DefaultAPIPrefix.
+func WithAPIPrefix(prefix string) Option {
+  return func(c *config) {
+    c.apiPrefix = prefix
+  }
+}
+
+// WithRegistryOptions passes options to the ModuleRegistry.
+func WithRegistryOptions(opts ...pocketframework.RegistryOption) Option {
+  return func(c *config) {
+    c.registryOptions = append(c.registryOptions, opts...)
+  }
+}
+
+// TestApp is a bootstrapped pocketbase app in a temporary directory with the modules mounted on its router.
+type TestApp struct {
+  core.App
+
+  Registry *pocketframework.ModuleRegistry
+
+  t       testing.TB
+  handler http.Handler
+
+  mu        sync.Mutex
+  superuser *core.Record
+
+  // eventsMu guards eventCalls separately, so hooks triggered while mu is held can still be counted
+  eventsMu   sync.Mutex
+  eventCalls map[string]int
+}
+
+// New creates and bootstraps a TestApp with the given modules. The app is terminated when the test finishes.
+func New(t testing.TB, modules []pocketframework.Module, opts ...Option) *TestApp {
+  t.Helper()
+
+  c := &config{apiPrefix: DefaultAPIPrefix}
+  for _, opt := range opts {
+    opt(c)
+  }
+
+  app := &TestApp{
+    App:        core.NewBaseApp(core.BaseAppConfig{DataDir: t.TempDir()}),
+    t:          t,
+    eventCalls: map[string]int{},
+  }
+
+  app.Registry = pocketframework.NewModuleRegistry(app.App, c.apiPrefix, c.registryOptions...)
+  for _, module := range modules {
+    app.Registry.Register(module)
+  }
+
+  if err := app.Registry.Init(); err != nil {
+    t.Fatalf("failed to init the module registry: %v", err)
+  }
+
+  if err := app.Bootstrap(); err != nil {
+    t.Fatalf("failed to bootstrap the app: %v", err)
+  }
+
+  app.countEvents()
+
+  r, err := apis.NewRouter(app.App)
+  if err != nil {
+    t.Fatalf("failed to create the router: %v", err)
+  }
+
+  serveEvent := &core.ServeEvent{App: app.App, Router: r}
+  err = app.OnServe().Trigger(
+    serveEvent, func(e *core.ServeEvent) error {
+      app.handler, err = e.Router.BuildMux()
+      return err
+    },
+  )
+  if err != nil {
+    t.Fatalf("failed to mount the routes: %v", err)
+  }
+
+  t.Cleanup(app.terminate)
+
+  return app
+}
+
+// Superuser returns a superuser, creating it on first use.
+func (a *TestApp) Superuser() *core.Record {
+  a.t.Helper()
+
+  a.mu.Lock()
+  defer a.mu.Unlock()
+
+  if a.superuser != nil {
+    return a.superuser
+  }
+
+  collection, err := a.FindCollectionByNameOrId(core.CollectionNameSuperusers)
+  if err != nil {
+    a.t.Fatalf("failed to find the superusers collection: %v", err)
+  }
+
+  superuser := core.NewRecord(collection)
+  superuser.SetEmail(DefaultSuperuserEmail)
+  superuser.SetPassword("1234567890")
+  if err := a.Save(superuser); err != nil {
+    a.t.Fatalf("failed to create the superuser: %v", err)
+  }
+  a.superuser = superuser
+
+  return superuser
+}
+
+// EventCalls returns how often each app hook has been triggered since the last ResetEventCalls, keyed by hook name,
+// e.g. "OnRecordCreate".
+func (a *TestApp) EventCalls() map[string]int {
+  a.eventsMu.Lock()
+  defer a.eventsMu.Unlock()
+
+  calls := make(map[string]int, len(a.eventCalls))
+  for name, count := range a.eventCalls {
+    calls[name] = count
+  }
+
+  return calls
+}
+
+// ResetEventCalls resets the counters of EventCalls.
+func (a *TestApp) ResetEventCalls() {
+  a.eventsMu.Lock()
+  defer a.eventsMu.Unlock()
+
+  a.eventCalls = map[string]int{}
+}
+
+// countEvents binds a handler to every hook of the app which counts how often it is triggered.
+func (a *TestApp) countEvents() {
+  value := reflect.ValueOf(a.App)
+
+  for i := 0; i < value.NumMethod(); i++ {
+    name := value.Type().Method(i).Name
+    method := value.Method(i)
+    if !strings.HasPrefix(name, "On") || method.Type().NumOut() != 1 {
+      continue
+    }
+
+    var hookValue reflect.Value
+    switch {
+    case method.Type().NumIn() == 0:
+      hookValue = method.Call(nil)[0]
+    case method.Type().NumIn() == 1 && method.Type().IsVariadic():
+      hookValue = method.Call(nil)[0]
+    default:
+      continue
+    }
+
+    bindFunc := hookValue.MethodByName("BindFunc")
+    if !bindFunc.IsValid() {
+      continue
+    }
+
+    fnType := bindFunc.Type().In(0)
+    counter := reflect.MakeFunc(
+      fnType, func(args []reflect.Value) []reflect.Value {
+        a.eventsMu.Lock()
+        a.eventCalls[name]++
+        a.eventsMu.Unlock()
+
+        return args[0].MethodByName("Next").Call(nil)
+      },
+    )
+    bindFunc.Call([]reflect.Value{counter})
+  }
+}
+
+func (a *TestApp) terminate() {
+  event := &core.TerminateEvent{App: a.App}
+  err := a.OnTerminate().Trigger(
+    event, func(e *core.TerminateEvent) error {
+      return e.App.ResetBootstrapState()
+    },
+  )
+  if err != nil {
+    a.t.Errorf("failed to terminate the app: %v", err)
+  }
+}
@@ -0,0 +1,137 @@
+package pocketframeworktest_test
+
+import (
+  "fmt"
+  "net/http"
+  "testing"
+
+  "github.com/leon-marzahn/pocketframework"
+  "github.com/leon-marzahn/pocketframework/pocketframeworktest"
+  "github.com/pocketbase/pocketbase/core"
+)
+
+type invoicesModule struct{}
+
+func (m *invoicesModule) Name() string {
+  return "invoices"
+}
+
+func (m *invoicesModule) Prefix() string {
+  return "/invoices"
+}
+
+func (m *invoicesModule) RegisterHooks(app pocketframework.ModuleAppHooks) error {
+  return nil
+}
+
+func (m *invoicesModule) Collections() []*core.Collection {
+  collection := core.NewBaseCollection("invoices")
+  collection.Fields.Add(&core.TextField{Name: "customer"})
+
+  return []*core.Collection{collection}
+}
+
+func (m *invoicesModule) RegisterRoutes(groups pocketframework.RouterGroups) error {
+  groups.Public.GET(
+    "/ping", func(e *core.RequestEvent) error {
+      return e.JSON(http.StatusOK, map[string]string{"status": "ok"})
+    },
+  )
+
+  groups.Public.POST(
+    "", func(e *core.RequestEvent) error {
+      collection, err := e.App.FindCachedCollectionByNameOrId("invoices")
+      if err != nil {
+        return err
+      }
+
+      record := core.NewRecord(collection)
+      record.Set("customer", "acme")
+      if err := e.App.Save(record); err != nil {
+        return err
+      }
+
+      return e.NoContent(http.StatusCreated)
+    },
+  )
+
+  groups.Admin.GET(
+    "/stats", func(e *core.RequestEvent) error {
+      return e.JSON(http.StatusOK, map[string]int{"open": 0})
+    },
+  )
+
+  return nil
+}
+
+func TestTestApp(t *testing.T) {
+  app := pocketframeworktest.New(t, []pocketframework.Module{&invoicesModule{}})
+
+  tests := []struct {
+    name      string
+    path      string
+    superuser bool
+    status    int
+  }{
+    {name: "guest on a public route", path: "/api/invoices/ping", status: http.StatusOK},
+    {name: "guest on an admin route", path: "/api/invoices/stats", status: http.StatusUnauthorized},
+    {name: "superuser on a public route", path: "/api/invoices/ping", superuser: true, status: http.StatusOK},
+    {name: "superuser on an admin route", path: "/api/invoices/stats", superuser: true, status: http.StatusOK},
+    {name: "unknown route", path: "/api/invoices/unknown", status: http.StatusNotFound},
+  }
+
+  for _, tt := range tests {
+    t.Run(
+      tt.name, func(t *testing.T) {
+        request := app.Request(http.MethodGet, tt.path)
+        if tt.superuser {
+          request.AsSuperuser()
+        }
+
+        request.Do().AssertStatus(tt.status)
+      },
+    )
+  }
+
+  app.Request(http.MethodGet, "/api/invoices/ping").Do().AssertJSON(map[string]string{"status": "ok"})
+}
+
+// recordingT records the errors of failed assertions instead of failing the test.
+type recordingT struct {
+  testing.TB
+
+  errors []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...any) {
+  r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertHookFired(t *testing.T) {
+  // the assertions of the app report to recorder, so the failing assertion does not fail this test
+  recorder := &recordingT{TB: t}
+  app := pocketframeworktest.New(recorder, []pocketframework.Module{&invoicesModule{}})
+
+  response := app.Request(http.MethodPost, "/api/invoices").Do().
+    AssertStatus(http.StatusCreated).
+    AssertHookFired("OnRecordCreate", 1).
+    AssertHookFired("OnRecordAfterCreateSuccess", 1).
+    AssertHookFired("OnRecordDelete", 0)
+
+  if calls := response.EventCalls(); calls["OnRecordCreate"] != 1 || calls["OnModelCreate"] != 1 {
+    t.Errorf("unexpected event calls %v", calls)
+  }
+  if len(recorder.errors) > 0 {
+    t.Fatalf("unexpected failures %v", recorder.errors)
+  }
+
+  // the counters are reset for every request
+  app.Request(http.MethodGet, "/api/invoices/ping").Do().AssertHookFired("OnRecordCreate", 1)
+
+  expected := "GET /api/invoices/ping: expected OnRecordCreate to be triggered 1 times, got 0"
+  if len(recorder.errors) != 1 || recorder.errors[0] != expected {
+    t.Errorf("expected the failure %q, got %v", expected, recorder.errors)
+  }
+}
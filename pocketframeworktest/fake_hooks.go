@@ -0,0 +1,537 @@
+package pocketframeworktest
+
+import (
+  "github.com/leon-marzahn/pocketframework"
+  "github.com/pocketbase/pocketbase/core"
+  "github.com/pocketbase/pocketbase/tools/hook"
+)
+
+// FakeHooks is an in-memory implementation of pocketframework.AppHooks for unit testing the RegisterHooks of a
+// module without a database or server:
+//
+//  hooks := pocketframeworktest.NewFakeHooks()
+//  _ = module.RegisterHooks(hooks.ModuleHooks())
+//  err := hooks.TriggerRecordCreate(record)
+//
+// Every hook is a fresh pocketbase hook without any handlers of pocketbase itself, so triggering it only runs the
+// handlers bound by the tested module.
+type FakeHooks struct {
+  // App is set as App of the events created by the trigger helpers. It is nil unless set by the test.
+  App core.App
+
+  moduleHooks *pocketframework.ScopedHooks
+
+  onBootstrap                         *hook.Hook[*core.BootstrapEvent]
+  onTerminate                         *hook.Hook[*core.TerminateEvent]
+  onBackupCreate                      *hook.Hook[*core.BackupEvent]
+  onBackupRestore                     *hook.Hook[*core.BackupEvent]
+  onModelValidate                     *hook.Hook[*core.ModelEvent]
+  onModelCreate                       *hook.Hook[*core.ModelEvent]
+  onModelCreateExecute                *hook.Hook[*core.ModelEvent]
+  onModelAfterCreateSuccess           *hook.Hook[*core.ModelEvent]
+  onModelAfterCreateError             *hook.Hook[*core.ModelErrorEvent]
+  onModelUpdate                       *hook.Hook[*core.ModelEvent]
+  onModelUpdateExecute                *hook.Hook[*core.ModelEvent]
+  onModelAfterUpdateSuccess           *hook.Hook[*core.ModelEvent]
+  onModelAfterUpdateError             *hook.Hook[*core.ModelErrorEvent]
+  onModelDelete                       *hook.Hook[*core.ModelEvent]
+  onModelDeleteExecute                *hook.Hook[*core.ModelEvent]
+  onModelAfterDeleteSuccess           *hook.Hook[*core.ModelEvent]
+  onModelAfterDeleteError             *hook.Hook[*core.ModelErrorEvent]
+  onRecordEnrich                      *hook.Hook[*core.RecordEnrichEvent]
+  onRecordValidate                    *hook.Hook[*core.RecordEvent]
+  onRecordCreate                      *hook.Hook[*core.RecordEvent]
+  onRecordCreateExecute               *hook.Hook[*core.RecordEvent]
+  onRecordAfterCreateSuccess          *hook.Hook[*core.RecordEvent]
+  onRecordAfterCreateError            *hook.Hook[*core.RecordErrorEvent]
+  onRecordUpdate                      *hook.Hook[*core.RecordEvent]
+  onRecordUpdateExecute               *hook.Hook[*core.RecordEvent]
+  onRecordAfterUpdateSuccess          *hook.Hook[*core.RecordEvent]
+  onRecordAfterUpdateError            *hook.Hook[*core.RecordErrorEvent]
+  onRecordDelete                      *hook.Hook[*core.RecordEvent]
+  onRecordDeleteExecute               *hook.Hook[*core.RecordEvent]
+  onRecordAfterDeleteSuccess          *hook.Hook[*core.RecordEvent]
+  onRecordAfterDeleteError            *hook.Hook[*core.RecordErrorEvent]
+  onCollectionValidate                *hook.Hook[*core.CollectionEvent]
+  onCollectionCreate                  *hook.Hook[*core.CollectionEvent]
+  onCollectionCreateExecute           *hook.Hook[*core.CollectionEvent]
+  onCollectionAfterCreateSuccess      *hook.Hook[*core.CollectionEvent]
+  onCollectionAfterCreateError        *hook.Hook[*core.CollectionErrorEvent]
+  onCollectionUpdate                  *hook.Hook[*core.CollectionEvent]
+  onCollectionUpdateExecute           *hook.Hook[*core.CollectionEvent]
+  onCollectionAfterUpdateSuccess      *hook.Hook[*core.CollectionEvent]
+  onCollectionAfterUpdateError        *hook.Hook[*core.CollectionErrorEvent]
+  onCollectionDelete                  *hook.Hook[*core.CollectionEvent]
+  onCollectionDeleteExecute           *hook.Hook[*core.CollectionEvent]
+  onCollectionAfterDeleteSuccess      *hook.Hook[*core.CollectionEvent]
+  onCollectionAfterDeleteError        *hook.Hook[*core.CollectionErrorEvent]
+  onMailerSend                        *hook.Hook[*core.MailerEvent]
+  onMailerRecordAuthAlertSend         *hook.Hook[*core.MailerRecordEvent]
+  onMailerRecordPasswordResetSend     *hook.Hook[*core.MailerRecordEvent]
+  onMailerRecordVerificationSend      *hook.Hook[*core.MailerRecordEvent]
+  onMailerRecordEmailChangeSend       *hook.Hook[*core.MailerRecordEvent]
+  onMailerRecordOTPSend               *hook.Hook[*core.MailerRecordEvent]
+  onRealtimeConnectRequest            *hook.Hook[*core.RealtimeConnectRequestEvent]
+  onRealtimeMessageSend               *hook.Hook[*core.RealtimeMessageEvent]
+  onRealtimeSubscribeRequest          *hook.Hook[*core.RealtimeSubscribeRequestEvent]
+  onSettingsListRequest               *hook.Hook[*core.SettingsListRequestEvent]
+  onSettingsUpdateRequest             *hook.Hook[*core.SettingsUpdateRequestEvent]
+  onSettingsReload                    *hook.Hook[*core.SettingsReloadEvent]
+  onFileDownloadRequest               *hook.Hook[*core.FileDownloadRequestEvent]
+  onFileTokenRequest                  *hook.Hook[*core.FileTokenRequestEvent]
+  onRecordAuthRequest                 *hook.Hook[*core.RecordAuthRequestEvent]
+  onRecordAuthWithPasswordRequest     *hook.Hook[*core.RecordAuthWithPasswordRequestEvent]
+  onRecordAuthWithOAuth2Request       *hook.Hook[*core.RecordAuthWithOAuth2RequestEvent]
+  onRecordAuthRefreshRequest          *hook.Hook[*core.RecordAuthRefreshRequestEvent]
+  onRecordRequestPasswordResetRequest *hook.Hook[*core.RecordRequestPasswordResetRequestEvent]
+  onRecordConfirmPasswordResetRequest *hook.Hook[*core.RecordConfirmPasswordResetRequestEvent]
+  onRecordRequestVerificationRequest  *hook.Hook[*core.RecordRequestVerificationRequestEvent]
+  onRecordConfirmVerificationRequest  *hook.Hook[*core.RecordConfirmVerificationRequestEvent]
+  onRecordRequestEmailChangeRequest   *hook.Hook[*core.RecordRequestEmailChangeRequestEvent]
+  onRecordConfirmEmailChangeRequest   *hook.Hook[*core.RecordConfirmEmailChangeRequestEvent]
+  onRecordRequestOTPRequest           *hook.Hook[*core.RecordCreateOTPRequestEvent]
+  onRecordAuthWithOTPRequest          *hook.Hook[*core.RecordAuthWithOTPRequestEvent]
+  onRecordsListRequest                *hook.Hook[*core.RecordsListRequestEvent]
+  onRecordViewRequest                 *hook.Hook[*core.RecordRequestEvent]
+  onRecordCreateRequest               *hook.Hook[*core.RecordRequestEvent]
+  onRecordUpdateRequest               *hook.Hook[*core.RecordRequestEvent]
+  onRecordDeleteRequest               *hook.Hook[*core.RecordRequestEvent]
+  onCollectionsListRequest            *hook.Hook[*core.CollectionsListRequestEvent]
+  onCollectionViewRequest             *hook.Hook[*core.CollectionRequestEvent]
+  onCollectionCreateRequest           *hook.Hook[*core.CollectionRequestEvent]
+  onCollectionUpdateRequest           *hook.Hook[*core.CollectionRequestEvent]
+  onCollectionDeleteRequest           *hook.Hook[*core.CollectionRequestEvent]
+  onCollectionsImportRequest          *hook.Hook[*core.CollectionsImportRequestEvent]
+  onBatchRequest                      *hook.Hook[*core.BatchRequestEvent]
+}
+
+var _ pocketframework.AppHooks = (*FakeHooks)(nil)
+
+// NewFakeHooks creates a FakeHooks without any bound handlers.
+func NewFakeHooks() *FakeHooks {
+  f := &FakeHooks{
+    onBootstrap:                         &hook.Hook[*core.BootstrapEvent]{},
+    onTerminate:                         &hook.Hook[*core.TerminateEvent]{},
+    onBackupCreate:                      &hook.Hook[*core.BackupEvent]{},
+    onBackupRestore:                     &hook.Hook[*core.BackupEvent]{},
+    onModelValidate:                     &hook.Hook[*core.ModelEvent]{},
+    onModelCreate:                       &hook.Hook[*core.ModelEvent]{},
+    onModelCreateExecute:                &hook.Hook[*core.ModelEvent]{},
+    onModelAfterCreateSuccess:           &hook.Hook[*core.ModelEvent]{},
+    onModelAfterCreateError:             &hook.Hook[*core.ModelErrorEvent]{},
+    onModelUpdate:                       &hook.Hook[*core.ModelEvent]{},
+    onModelUpdateExecute:                &hook.Hook[*core.ModelEvent]{},
+    onModelAfterUpdateSuccess:           &hook.Hook[*core.ModelEvent]{},
+    onModelAfterUpdateError:             &hook.Hook[*core.ModelErrorEvent]{},
+    onModelDelete:                       &hook.Hook[*core.ModelEvent]{},
+    onModelDeleteExecute:                &hook.Hook[*core.ModelEvent]{},
+    onModelAfterDeleteSuccess:           &hook.Hook[*core.ModelEvent]{},
+    onModelAfterDeleteError:             &hook.Hook[*core.ModelErrorEvent]{},
+    onRecordEnrich:                      &hook.Hook[*core.RecordEnrichEvent]{},
+    onRecordValidate:                    &hook.Hook[*core.RecordEvent]{},
+    onRecordCreate:                      &hook.Hook[*core.RecordEvent]{},
+    onRecordCreateExecute:               &hook.Hook[*core.RecordEvent]{},
+    onRecordAfterCreateSuccess:          &hook.Hook[*core.RecordEvent]{},
+    onRecordAfterCreateError:            &hook.Hook[*core.RecordErrorEvent]{},
+    onRecordUpdate:                      &hook.Hook[*core.RecordEvent]{},
+    onRecordUpdateExecute:               &hook.Hook[*core.RecordEvent]{},
+    onRecordAfterUpdateSuccess:          &hook.Hook[*core.RecordEvent]{},
+    onRecordAfterUpdateError:            &hook.Hook[*core.RecordErrorEvent]{},
+    onRecordDelete:                      &hook.Hook[*core.RecordEvent]{},
+    onRecordDeleteExecute:               &hook.Hook[*core.RecordEvent]{},
+    onRecordAfterDeleteSuccess:          &hook.Hook[*core.RecordEvent]{},
+    onRecordAfterDeleteError:            &hook.Hook[*core.RecordErrorEvent]{},
+    onCollectionValidate:                &hook.Hook[*core.CollectionEvent]{},
+    onCollectionCreate:                  &hook.Hook[*core.CollectionEvent]{},
+    onCollectionCreateExecute:           &hook.Hook[*core.CollectionEvent]{},
+    onCollectionAfterCreateSuccess:      &hook.Hook[*core.CollectionEvent]{},
+    onCollectionAfterCreateError:        &hook.Hook[*core.CollectionErrorEvent]{},
+    onCollectionUpdate:                  &hook.Hook[*core.CollectionEvent]{},
+    onCollectionUpdateExecute:           &hook.Hook[*core.CollectionEvent]{},
+    onCollectionAfterUpdateSuccess:      &hook.Hook[*core.CollectionEvent]{},
+    onCollectionAfterUpdateError:        &hook.Hook[*core.CollectionErrorEvent]{},
+    onCollectionDelete:                  &hook.Hook[*core.CollectionEvent]{},
+    onCollectionDeleteExecute:           &hook.Hook[*core.CollectionEvent]{},
+    onCollectionAfterDeleteSuccess:      &hook.Hook[*core.CollectionEvent]{},
+    onCollectionAfterDeleteError:        &hook.Hook[*core.CollectionErrorEvent]{},
+    onMailerSend:                        &hook.Hook[*core.MailerEvent]{},
+    onMailerRecordAuthAlertSend:         &hook.Hook[*core.MailerRecordEvent]{},
+    onMailerRecordPasswordResetSend:     &hook.Hook[*core.MailerRecordEvent]{},
+    onMailerRecordVerificationSend:      &hook.Hook[*core.MailerRecordEvent]{},
+    onMailerRecordEmailChangeSend:       &hook.Hook[*core.MailerRecordEvent]{},
+    onMailerRecordOTPSend:               &hook.Hook[*core.MailerRecordEvent]{},
+    onRealtimeConnectRequest:            &hook.Hook[*core.RealtimeConnectRequestEvent]{},
+    onRealtimeMessageSend:               &hook.Hook[*core.RealtimeMessageEvent]{},
+    onRealtimeSubscribeRequest:          &hook.Hook[*core.RealtimeSubscribeRequestEvent]{},
+    onSettingsListRequest:               &hook.Hook[*core.SettingsListRequestEvent]{},
+    onSettingsUpdateRequest:             &hook.Hook[*core.SettingsUpdateRequestEvent]{},
+    onSettingsReload:                    &hook.Hook[*core.SettingsReloadEvent]{},
+    onFileDownloadRequest:               &hook.Hook[*core.FileDownloadRequestEvent]{},
+    onFileTokenRequest:                  &hook.Hook[*core.FileTokenRequestEvent]{},
+    onRecordAuthRequest:                 &hook.Hook[*core.RecordAuthRequestEvent]{},
+    onRecordAuthWithPasswordRequest:     &hook.Hook[*core.RecordAuthWithPasswordRequestEvent]{},
+    onRecordAuthWithOAuth2Request:       &hook.Hook[*core.RecordAuthWithOAuth2RequestEvent]{},
+    onRecordAuthRefreshRequest:          &hook.Hook[*core.RecordAuthRefreshRequestEvent]{},
+    onRecordRequestPasswordResetRequest: &hook.Hook[*core.RecordRequestPasswordResetRequestEvent]{},
+    onRecordConfirmPasswordResetRequest: &hook.Hook[*core.RecordConfirmPasswordResetRequestEvent]{},
+    onRecordRequestVerificationRequest:  &hook.Hook[*core.RecordRequestVerificationRequestEvent]{},
+    onRecordConfirmVerificationRequest:  &hook.Hook[*core.RecordConfirmVerificationRequestEvent]{},
+    onRecordRequestEmailChangeRequest:   &hook.Hook[*core.RecordRequestEmailChangeRequestEvent]{},
+    onRecordConfirmEmailChangeRequest:   &hook.Hook[*core.RecordConfirmEmailChangeRequestEvent]{},
+    onRecordRequestOTPRequest:           &hook.Hook[*core.RecordCreateOTPRequestEvent]{},
+    onRecordAuthWithOTPRequest:          &hook.Hook[*core.RecordAuthWithOTPRequestEvent]{},
+    onRecordsListRequest:                &hook.Hook[*core.RecordsListRequestEvent]{},
+    onRecordViewRequest:                 &hook.Hook[*core.RecordRequestEvent]{},
+    onRecordCreateRequest:               &hook.Hook[*core.RecordRequestEvent]{},
+    onRecordUpdateRequest:               &hook.Hook[*core.RecordRequestEvent]{},
+    onRecordDeleteRequest:               &hook.Hook[*core.RecordRequestEvent]{},
+    onCollectionsListRequest:            &hook.Hook[*core.CollectionsListRequestEvent]{},
+    onCollectionViewRequest:             &hook.Hook[*core.CollectionRequestEvent]{},
+    onCollectionCreateRequest:           &hook.Hook[*core.CollectionRequestEvent]{},
+    onCollectionUpdateRequest:           &hook.Hook[*core.CollectionRequestEvent]{},
+    onCollectionDeleteRequest:           &hook.Hook[*core.CollectionRequestEvent]{},
+    onCollectionsImportRequest:          &hook.Hook[*core.CollectionsImportRequestEvent]{},
+    onBatchRequest:                      &hook.Hook[*core.BatchRequestEvent]{},
+  }
+  f.moduleHooks = pocketframework.NewScopedHooks(f)
+
+  return f
+}
+
+// ModuleHooks returns the ModuleAppHooks to pass to the RegisterHooks of the tested module.
+func (f *FakeHooks) ModuleHooks() *pocketframework.ScopedHooks {
+  return f.moduleHooks
+}
+
+// Bindings returns the handlers bound through ModuleHooks.
+func (f *FakeHooks) Bindings() []pocketframework.HookBinding {
+  return f.moduleHooks.Bindings()
+}
+
+func (f *FakeHooks) OnBootstrap() *hook.Hook[*core.BootstrapEvent] {
+  return f.onBootstrap
+}
+
+func (f *FakeHooks) OnTerminate() *hook.Hook[*core.TerminateEvent] {
+  return f.onTerminate
+}
+
+func (f *FakeHooks) OnBackupCreate() *hook.Hook[*core.BackupEvent] {
+  return f.onBackupCreate
+}
+
+func (f *FakeHooks) OnBackupRestore() *hook.Hook[*core.BackupEvent] {
+  return f.onBackupRestore
+}
+
+func (f *FakeHooks) OnModelValidate(tags ...string) *hook.TaggedHook[*core.ModelEvent] {
+  return hook.NewTaggedHook(f.onModelValidate, tags...)
+}
+
+func (f *FakeHooks) OnModelCreate(tags ...string) *hook.TaggedHook[*core.ModelEvent] {
+  return hook.NewTaggedHook(f.onModelCreate, tags...)
+}
+
+func (f *FakeHooks) OnModelCreateExecute(tags ...string) *hook.TaggedHook[*core.ModelEvent] {
+  return hook.NewTaggedHook(f.onModelCreateExecute, tags...)
+}
+
+func (f *FakeHooks) OnModelAfterCreateSuccess(tags ...string) *hook.TaggedHook[*core.ModelEvent] {
+  return hook.NewTaggedHook(f.onModelAfterCreateSuccess, tags...)
+}
+
+func (f *FakeHooks) OnModelAfterCreateError(tags ...string) *hook.TaggedHook[*core.ModelErrorEvent] {
+  return hook.NewTaggedHook(f.onModelAfterCreateError, tags...)
+}
+
+func (f *FakeHooks) OnModelUpdate(tags ...string) *hook.TaggedHook[*core.ModelEvent] {
+  return hook.NewTaggedHook(f.onModelUpdate, tags...)
+}
+
+func (f *FakeHooks) OnModelUpdateExecute(tags ...string) *hook.TaggedHook[*core.ModelEvent] {
+  return hook.NewTaggedHook(f.onModelUpdateExecute, tags...)
+}
+
+func (f *FakeHooks) OnModelAfterUpdateSuccess(tags ...string) *hook.TaggedHook[*core.ModelEvent] {
+  return hook.NewTaggedHook(f.onModelAfterUpdateSuccess, tags...)
+}
+
+func (f *FakeHooks) OnModelAfterUpdateError(tags ...string) *hook.TaggedHook[*core.ModelErrorEvent] {
+  return hook.NewTaggedHook(f.onModelAfterUpdateError, tags...)
+}
+
+func (f *FakeHooks) OnModelDelete(tags ...string) *hook.TaggedHook[*core.ModelEvent] {
+  return hook.NewTaggedHook(f.onModelDelete, tags...)
+}
+
+func (f *FakeHooks) OnModelDeleteExecute(tags ...string) *hook.TaggedHook[*core.ModelEvent] {
+  return hook.NewTaggedHook(f.onModelDeleteExecute, tags...)
+}
+
+func (f *FakeHooks) OnModelAfterDeleteSuccess(tags ...string) *hook.TaggedHook[*core.ModelEvent] {
+  return hook.NewTaggedHook(f.onModelAfterDeleteSuccess, tags...)
+}
+
+func (f *FakeHooks) OnModelAfterDeleteError(tags ...string) *hook.TaggedHook[*core.ModelErrorEvent] {
+  return hook.NewTaggedHook(f.onModelAfterDeleteError, tags...)
+}
+
+func (f *FakeHooks) OnRecordEnrich(tags ...string) *hook.TaggedHook[*core.RecordEnrichEvent] {
+  return hook.NewTaggedHook(f.onRecordEnrich, tags...)
+}
+
+func (f *FakeHooks) OnRecordValidate(tags ...string) *hook.TaggedHook[*core.RecordEvent] {
+  return hook.NewTaggedHook(f.onRecordValidate, tags...)
+}
+
+func (f *FakeHooks) OnRecordCreate(tags ...string) *hook.TaggedHook[*core.RecordEvent] {
+  return hook.NewTaggedHook(f.onRecordCreate, tags...)
+}
+
+func (f *FakeHooks) OnRecordCreateExecute(tags ...string) *hook.TaggedHook[*core.RecordEvent] {
+  return hook.NewTaggedHook(f.onRecordCreateExecute, tags...)
+}
+
+func (f *FakeHooks) OnRecordAfterCreateSuccess(tags ...string) *hook.TaggedHook[*core.RecordEvent] {
+  return hook.NewTaggedHook(f.onRecordAfterCreateSuccess, tags...)
+}
+
+func (f *FakeHooks) OnRecordAfterCreateError(tags ...string) *hook.TaggedHook[*core.RecordErrorEvent] {
+  return hook.NewTaggedHook(f.onRecordAfterCreateError, tags...)
+}
+
+func (f *FakeHooks) OnRecordUpdate(tags ...string) *hook.TaggedHook[*core.RecordEvent] {
+  return hook.NewTaggedHook(f.onRecordUpdate, tags...)
+}
+
+func (f *FakeHooks) OnRecordUpdateExecute(tags ...string) *hook.TaggedHook[*core.RecordEvent] {
+  return hook.NewTaggedHook(f.onRecordUpdateExecute, tags...)
+}
+
+func (f *FakeHooks) OnRecordAfterUpdateSuccess(tags ...string) *hook.TaggedHook[*core.RecordEvent] {
+  return hook.NewTaggedHook(f.onRecordAfterUpdateSuccess, tags...)
+}
+
+func (f *FakeHooks) OnRecordAfterUpdateError(tags ...string) *hook.TaggedHook[*core.RecordErrorEvent] {
+  return hook.NewTaggedHook(f.onRecordAfterUpdateError, tags...)
+}
+
+func (f *FakeHooks) OnRecordDelete(tags ...string) *hook.TaggedHook[*core.RecordEvent] {
+  return hook.NewTaggedHook(f.onRecordDelete, tags...)
+}
+
+func (f *FakeHooks) OnRecordDeleteExecute(tags ...string) *hook.TaggedHook[*core.RecordEvent] {
+  return hook.NewTaggedHook(f.onRecordDeleteExecute, tags...)
+}
+
+func (f *FakeHooks) OnRecordAfterDeleteSuccess(tags ...string) *hook.TaggedHook[*core.RecordEvent] {
+  return hook.NewTaggedHook(f.onRecordAfterDeleteSuccess, tags...)
+}
+
+func (f *FakeHooks) OnRecordAfterDeleteError(tags ...string) *hook.TaggedHook[*core.RecordErrorEvent] {
+  return hook.NewTaggedHook(f.onRecordAfterDeleteError, tags...)
+}
+
+func (f *FakeHooks) OnCollectionValidate(tags ...string) *hook.TaggedHook[*core.CollectionEvent] {
+  return hook.NewTaggedHook(f.onCollectionValidate, tags...)
+}
+
+func (f *FakeHooks) OnCollectionCreate(tags ...string) *hook.TaggedHook[*core.CollectionEvent] {
+  return hook.NewTaggedHook(f.onCollectionCreate, tags...)
+}
+
+func (f *FakeHooks) OnCollectionCreateExecute(tags ...string) *hook.TaggedHook[*core.CollectionEvent] {
+  return hook.NewTaggedHook(f.onCollectionCreateExecute, tags...)
+}
+
+func (f *FakeHooks) OnCollectionAfterCreateSuccess(tags ...string) *hook.TaggedHook[*core.CollectionEvent] {
+  return hook.NewTaggedHook(f.onCollectionAfterCreateSuccess, tags...)
+}
+
+func (f *FakeHooks) OnCollectionAfterCreateError(tags ...string) *hook.TaggedHook[*core.CollectionErrorEvent] {
+  return hook.NewTaggedHook(f.onCollectionAfterCreateError, tags...)
+}
+
+func (f *FakeHooks) OnCollectionUpdate(tags ...string) *hook.TaggedHook[*core.CollectionEvent] {
+  return hook.NewTaggedHook(f.onCollectionUpdate, tags...)
+}
+
+func (f *FakeHooks) OnCollectionUpdateExecute(tags ...string) *hook.TaggedHook[*core.CollectionEvent] {
+  return hook.NewTaggedHook(f.onCollectionUpdateExecute, tags...)
+}
+
+func (f *FakeHooks) OnCollectionAfterUpdateSuccess(tags ...string) *hook.TaggedHook[*core.CollectionEvent] {
+  return hook.NewTaggedHook(f.onCollectionAfterUpdateSuccess, tags...)
+}
+
+func (f *FakeHooks) OnCollectionAfterUpdateError(tags ...string) *hook.TaggedHook[*core.CollectionErrorEvent] {
+  return hook.NewTaggedHook(f.onCollectionAfterUpdateError, tags...)
+}
+
+func (f *FakeHooks) OnCollectionDelete(tags ...string) *hook.TaggedHook[*core.CollectionEvent] {
+  return hook.NewTaggedHook(f.onCollectionDelete, tags...)
+}
+
+func (f *FakeHooks) OnCollectionDeleteExecute(tags ...string) *hook.TaggedHook[*core.CollectionEvent] {
+  return hook.NewTaggedHook(f.onCollectionDeleteExecute, tags...)
+}
+
+func (f *FakeHooks) OnCollectionAfterDeleteSuccess(tags ...string) *hook.TaggedHook[*core.CollectionEvent] {
+  return hook.NewTaggedHook(f.onCollectionAfterDeleteSuccess, tags...)
+}
+
+func (f *FakeHooks) OnCollectionAfterDeleteError(tags ...string) *hook.TaggedHook[*core.CollectionErrorEvent] {
+  return hook.NewTaggedHook(f.onCollectionAfterDeleteError, tags...)
+}
+
+func (f *FakeHooks) OnMailerSend() *hook.Hook[*core.MailerEvent] {
+  return f.onMailerSend
+}
+
+func (f *FakeHooks) OnMailerRecordAuthAlertSend(tags ...string) *hook.TaggedHook[*core.MailerRecordEvent] {
+  return hook.NewTaggedHook(f.onMailerRecordAuthAlertSend, tags...)
+}
+
+func (f *FakeHooks) OnMailerRecordPasswordResetSend(tags ...string) *hook.TaggedHook[*core.MailerRecordEvent] {
+  return hook.NewTaggedHook(f.onMailerRecordPasswordResetSend, tags...)
+}
+
+func (f *FakeHooks) OnMailerRecordVerificationSend(tags ...string) *hook.TaggedHook[*core.MailerRecordEvent] {
+  return hook.NewTaggedHook(f.onMailerRecordVerificationSend, tags...)
+}
+
+func (f *FakeHooks) OnMailerRecordEmailChangeSend(tags ...string) *hook.TaggedHook[*core.MailerRecordEvent] {
+  return hook.NewTaggedHook(f.onMailerRecordEmailChangeSend, tags...)
+}
+
+func (f *FakeHooks) OnMailerRecordOTPSend(tags ...string) *hook.TaggedHook[*core.MailerRecordEvent] {
+  return hook.NewTaggedHook(f.onMailerRecordOTPSend, tags...)
+}
+
+func (f *FakeHooks) OnRealtimeConnectRequest() *hook.Hook[*core.RealtimeConnectRequestEvent] {
+  return f.onRealtimeConnectRequest
+}
+
+func (f *FakeHooks) OnRealtimeMessageSend() *hook.Hook[*core.RealtimeMessageEvent] {
+  return f.onRealtimeMessageSend
+}
+
+func (f *FakeHooks) OnRealtimeSubscribeRequest() *hook.Hook[*core.RealtimeSubscribeRequestEvent] {
+  return f.onRealtimeSubscribeRequest
+}
+
+func (f *FakeHooks) OnSettingsListRequest() *hook.Hook[*core.SettingsListRequestEvent] {
+  return f.onSettingsListRequest
+}
+
+func (f *FakeHooks) OnSettingsUpdateRequest() *hook.Hook[*core.SettingsUpdateRequestEvent] {
+  return f.onSettingsUpdateRequest
+}
+
+func (f *FakeHooks) OnSettingsReload() *hook.Hook[*core.SettingsReloadEvent] {
+  return f.onSettingsReload
+}
+
+func (f *FakeHooks) OnFileDownloadRequest(tags ...string) *hook.TaggedHook[*core.FileDownloadRequestEvent] {
+  return hook.NewTaggedHook(f.onFileDownloadRequest, tags...)
+}
+
+func (f *FakeHooks) OnFileTokenRequest(tags ...string) *hook.TaggedHook[*core.FileTokenRequestEvent] {
+  return hook.NewTaggedHook(f.onFileTokenRequest, tags...)
+}
+
+func (f *FakeHooks) OnRecordAuthRequest(tags ...string) *hook.TaggedHook[*core.RecordAuthRequestEvent] {
+  return hook.NewTaggedHook(f.onRecordAuthRequest, tags...)
+}
+
+func (f *FakeHooks) OnRecordAuthWithPasswordRequest(tags ...string) *hook.TaggedHook[*core.RecordAuthWithPasswordRequestEvent] {
+  return hook.NewTaggedHook(f.onRecordAuthWithPasswordRequest, tags...)
+}
+
+func (f *FakeHooks) OnRecordAuthWithOAuth2Request(tags ...string) *hook.TaggedHook[*core.RecordAuthWithOAuth2RequestEvent] {
+  return hook.NewTaggedHook(f.onRecordAuthWithOAuth2Request, tags...)
+}
+
+func (f *FakeHooks) OnRecordAuthRefreshRequest(tags ...string) *hook.TaggedHook[*core.RecordAuthRefreshRequestEvent] {
+  return hook.NewTaggedHook(f.onRecordAuthRefreshRequest, tags...)
+}
+
+func (f *FakeHooks) OnRecordRequestPasswordResetRequest(tags ...string) *hook.TaggedHook[*core.RecordRequestPasswordResetRequestEvent] {
+  return hook.NewTaggedHook(f.onRecordRequestPasswordResetRequest, tags...)
+}
+
+func (f *FakeHooks) OnRecordConfirmPasswordResetRequest(tags ...string) *hook.TaggedHook[*core.RecordConfirmPasswordResetRequestEvent] {
+  return hook.NewTaggedHook(f.onRecordConfirmPasswordResetRequest, tags...)
+}
+
+func (f *FakeHooks) OnRecordRequestVerificationRequest(tags ...string) *hook.TaggedHook[*core.RecordRequestVerificationRequestEvent] {
+  return hook.NewTaggedHook(f.onRecordRequestVerificationRequest, tags...)
+}
+
+func (f *FakeHooks) OnRecordConfirmVerificationRequest(tags ...string) *hook.TaggedHook[*core.RecordConfirmVerificationRequestEvent] {
+  return hook.NewTaggedHook(f.onRecordConfirmVerificationRequest, tags...)
+}
+
+func (f *FakeHooks) OnRecordRequestEmailChangeRequest(tags ...string) *hook.TaggedHook[*core.RecordRequestEmailChangeRequestEvent] {
+  return hook.NewTaggedHook(f.onRecordRequestEmailChangeRequest, tags...)
+}
+
+func (f *FakeHooks) OnRecordConfirmEmailChangeRequest(tags ...string) *hook.TaggedHook[*core.RecordConfirmEmailChangeRequestEvent] {
+  return hook.NewTaggedHook(f.onRecordConfirmEmailChangeRequest, tags...)
+}
+
+func (f *FakeHooks) OnRecordRequestOTPRequest(tags ...string) *hook.TaggedHook[*core.RecordCreateOTPRequestEvent] {
+  return hook.NewTaggedHook(f.onRecordRequestOTPRequest, tags...)
+}
+
+func (f *FakeHooks) OnRecordAuthWithOTPRequest(tags ...string) *hook.TaggedHook[*core.RecordAuthWithOTPRequestEvent] {
+  return hook.NewTaggedHook(f.onRecordAuthWithOTPRequest, tags...)
+}
+
+func (f *FakeHooks) OnRecordsListRequest(tags ...string) *hook.TaggedHook[*core.RecordsListRequestEvent] {
+  return hook.NewTaggedHook(f.onRecordsListRequest, tags...)
+}
+
+func (f *FakeHooks) OnRecordViewRequest(tags ...string) *hook.TaggedHook[*core.RecordRequestEvent] {
+  return hook.NewTaggedHook(f.onRecordViewRequest, tags...)
+}
+
+func (f *FakeHooks) OnRecordCreateRequest(tags ...string) *hook.TaggedHook[*core.RecordRequestEvent] {
+  return hook.NewTaggedHook(f.onRecordCreateRequest, tags...)
+}
+
+func (f *FakeHooks) OnRecordUpdateRequest(tags ...string) *hook.TaggedHook[*core.RecordRequestEvent] {
+  return hook.NewTaggedHook(f.onRecordUpdateRequest, tags...)
+}
+
+func (f *FakeHooks) OnRecordDeleteRequest(tags ...string) *hook.TaggedHook[*core.RecordRequestEvent] {
+  return hook.NewTaggedHook(f.onRecordDeleteRequest, tags...)
+}
+
+func (f *FakeHooks) OnCollectionsListRequest() *hook.Hook[*core.CollectionsListRequestEvent] {
+  return f.onCollectionsListRequest
+}
+
+func (f *FakeHooks) OnCollectionViewRequest() *hook.Hook[*core.CollectionRequestEvent] {
+  return f.onCollectionViewRequest
+}
+
+func (f *FakeHooks) OnCollectionCreateRequest() *hook.Hook[*core.CollectionRequestEvent] {
+  return f.onCollectionCreateRequest
+}
+
+func (f *FakeHooks) OnCollectionUpdateRequest() *hook.Hook[*core.CollectionRequestEvent] {
+  return f.onCollectionUpdateRequest
+}
+
+func (f *FakeHooks) OnCollectionDeleteRequest() *hook.Hook[*core.CollectionRequestEvent] {
+  return f.onCollectionDeleteRequest
+}
+
+func (f *FakeHooks) OnCollectionsImportRequest() *hook.Hook[*core.CollectionsImportRequestEvent] {
+  return f.onCollectionsImportRequest
+}
+
+func (f *FakeHooks) OnBatchRequest() *hook.Hook[*core.BatchRequestEvent] {
+  return f.onBatchRequest
+}
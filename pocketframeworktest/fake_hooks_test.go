@@ -0,0 +1,219 @@
+package pocketframeworktest_test
+
+import (
+  "errors"
+  "net/http"
+  "strings"
+  "testing"
+
+  "github.com/leon-marzahn/pocketframework"
+  "github.com/leon-marzahn/pocketframework/pocketframeworktest"
+  "github.com/pocketbase/pocketbase/core"
+  "github.com/pocketbase/pocketbase/tools/hook"
+  "github.com/pocketbase/pocketbase/tools/mailer"
+)
+
+// auditModule records the hooks it handles.
+type auditModule struct {
+  calls []string
+}
+
+func (m *auditModule) Prefix() string {
+  return ""
+}
+
+func (m *auditModule) record(name string) func(e *core.RecordEvent) error {
+  return func(e *core.RecordEvent) error {
+    m.calls = append(m.calls, name+" "+e.Record.Collection().Name)
+    return e.Next()
+  }
+}
+
+func (m *auditModule) RegisterHooks(app pocketframework.ModuleAppHooks) error {
+  app.OnRecordCreate("invoices").BindFunc(m.record("create"))
+  app.OnRecordAfterCreateSuccess().BindFunc(m.record("created"))
+  app.OnRecordUpdate("invoices").BindFunc(m.record("update"))
+  app.OnRecordAfterUpdateSuccess("invoices").BindFunc(m.record("updated"))
+  app.OnRecordDelete("invoices").BindFunc(
+    func(e *core.RecordEvent) error {
+      if e.Record.GetBool("locked") {
+        return errors.New("locked invoices cannot be deleted")
+      }
+      return m.record("delete")(e)
+    },
+  )
+  app.OnRecordAfterDeleteSuccess("invoices").BindFunc(m.record("deleted"))
+
+  return nil
+}
+
+func (m *auditModule) RegisterRoutes(groups pocketframework.RouterGroups) error {
+  return nil
+}
+
+func newInvoice() *core.Record {
+  collection := core.NewBaseCollection("invoices")
+  collection.Fields.Add(&core.BoolField{Name: "locked"})
+
+  return core.NewRecord(collection)
+}
+
+func TestFakeHooksRecordTriggers(t *testing.T) {
+  hooks := pocketframeworktest.NewFakeHooks()
+  module := &auditModule{}
+  if err := module.RegisterHooks(hooks.ModuleHooks()); err != nil {
+    t.Fatal(err)
+  }
+
+  if bindings := hooks.Bindings(); len(bindings) != 6 || bindings[0].Hook != "OnRecordCreate" || bindings[0].Tags[0] != "invoices" {
+    t.Fatalf("expected the bindings of the module, got %+v", bindings)
+  }
+
+  tests := []struct {
+    name    string
+    trigger func() error
+    calls   []string
+    err     string
+  }{
+    {
+      name:    "create",
+      trigger: func() error { return hooks.TriggerRecordCreate(newInvoice()) },
+      calls:   []string{"create invoices", "created invoices"},
+    },
+    {
+      name: "create of another collection",
+      trigger: func() error {
+        return hooks.TriggerRecordCreate(core.NewRecord(core.NewBaseCollection("customers")))
+      },
+      calls: []string{"created customers"},
+    },
+    {
+      name:    "update",
+      trigger: func() error { return hooks.TriggerRecordUpdate(newInvoice()) },
+      calls:   []string{"update invoices", "updated invoices"},
+    },
+    {
+      name: "update of another collection",
+      trigger: func() error {
+        return hooks.TriggerRecordUpdate(core.NewRecord(core.NewBaseCollection("customers")))
+      },
+      calls: []string{},
+    },
+    {
+      name:    "delete",
+      trigger: func() error { return hooks.TriggerRecordDelete(newInvoice()) },
+      calls:   []string{"delete invoices", "deleted invoices"},
+    },
+    {
+      name: "failed delete",
+      trigger: func() error {
+        invoice := newInvoice()
+        invoice.Set("locked", true)
+        return hooks.TriggerRecordDelete(invoice)
+      },
+      calls: []string{},
+      err:   "locked invoices cannot be deleted",
+    },
+  }
+
+  for _, tt := range tests {
+    t.Run(
+      tt.name, func(t *testing.T) {
+        module.calls = []string{}
+
+        err := tt.trigger()
+        if tt.err == "" && err != nil {
+          t.Fatalf("unexpected error: %v", err)
+        }
+        if tt.err != "" && (err == nil || err.Error() != tt.err) {
+          t.Fatalf("expected the error %q, got %v", tt.err, err)
+        }
+
+        if strings.Join(module.calls, ",") != strings.Join(tt.calls, ",") {
+          t.Fatalf("expected the calls %v, got %v", tt.calls, module.calls)
+        }
+      },
+    )
+  }
+}
+
+func TestFakeHooksOrder(t *testing.T) {
+  hooks := pocketframeworktest.NewFakeHooks()
+  app := hooks.ModuleHooks()
+
+  calls := []string{}
+  record := func(name string) func(e *core.RecordEvent) error {
+    return func(e *core.RecordEvent) error {
+      calls = append(calls, name)
+      return e.Next()
+    }
+  }
+
+  app.OnRecordCreate().BindFunc(record("first"))
+  app.OnRecordCreate().BindFunc(record("second"))
+  app.OnRecordCreate().Bind(&hook.Handler[*core.RecordEvent]{Func: record("early"), Priority: -1})
+  app.OnRecordCreate().BindFunc(
+    func(e *core.RecordEvent) error {
+      calls = append(calls, "stop")
+      return nil
+    },
+  )
+  app.OnRecordCreate().BindFunc(record("skipped"))
+
+  if err := hooks.TriggerRecordCreate(newInvoice()); err != nil {
+    t.Fatal(err)
+  }
+
+  expected := "early,first,second,stop"
+  if strings.Join(calls, ",") != expected {
+    t.Fatalf("expected the handlers to run in the order %s, got %v", expected, calls)
+  }
+}
+
+func TestFakeHooksTriggers(t *testing.T) {
+  t.Run(
+    "mailer send", func(t *testing.T) {
+      hooks := pocketframeworktest.NewFakeHooks()
+
+      var subject string
+      hooks.ModuleHooks().OnMailerSend().BindFunc(
+        func(e *core.MailerEvent) error {
+          subject = e.Message.Subject
+          return e.Next()
+        },
+      )
+
+      if err := hooks.TriggerMailerSend(&mailer.Message{Subject: "Your invoice"}); err != nil {
+        t.Fatal(err)
+      }
+      if subject != "Your invoice" {
+        t.Fatalf("expected the message to be passed to the handler, got subject %q", subject)
+      }
+    },
+  )
+
+  t.Run(
+    "record auth request", func(t *testing.T) {
+      hooks := pocketframeworktest.NewFakeHooks()
+      hooks.ModuleHooks().OnRecordAuthRequest("users").BindFunc(
+        func(e *core.RecordAuthRequestEvent) error {
+          if e.AuthMethod != "password" || e.Request.URL.Path != "/api/collections/users/auth-with-password" {
+            t.Errorf("unexpected event %s %s", e.AuthMethod, e.Request.URL.Path)
+          }
+          return e.JSON(http.StatusOK, map[string]string{"record": e.Record.Id})
+        },
+      )
+
+      user := core.NewRecord(core.NewAuthCollection("users"))
+      user.Id = "u1"
+
+      recorder, err := hooks.TriggerRecordAuthRequest(user, "password")
+      if err != nil {
+        t.Fatal(err)
+      }
+      if recorder.Code != http.StatusOK || strings.TrimSpace(recorder.Body.String()) != `{"record":"u1"}` {
+        t.Fatalf("expected the response of the handler, got %d %s", recorder.Code, recorder.Body)
+      }
+    },
+  )
+}
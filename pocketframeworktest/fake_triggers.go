@@ -0,0 +1,73 @@
+package pocketframeworktest
+
+import (
+  "context"
+  "net/http"
+  "net/http/httptest"
+
+  "github.com/pocketbase/pocketbase/core"
+  "github.com/pocketbase/pocketbase/tools/mailer"
+)
+
+// TriggerRecordCreate triggers OnRecordCreate and OnRecordAfterCreateSuccess for the given record, like a successful
+// App.Save of a new record.
+func (f *FakeHooks) TriggerRecordCreate(record *core.Record) error {
+  event := f.newRecordEvent(record, core.ModelEventTypeCreate)
+  if err := f.onRecordCreate.Trigger(event); err != nil {
+    return err
+  }
+
+  return f.onRecordAfterCreateSuccess.Trigger(f.newRecordEvent(record, core.ModelEventTypeCreate))
+}
+
+// TriggerRecordUpdate triggers OnRecordUpdate and OnRecordAfterUpdateSuccess for the given record, like a successful
+// App.Save of an existing record.
+func (f *FakeHooks) TriggerRecordUpdate(record *core.Record) error {
+  event := f.newRecordEvent(record, core.ModelEventTypeUpdate)
+  if err := f.onRecordUpdate.Trigger(event); err != nil {
+    return err
+  }
+
+  return f.onRecordAfterUpdateSuccess.Trigger(f.newRecordEvent(record, core.ModelEventTypeUpdate))
+}
+
+// TriggerRecordDelete triggers OnRecordDelete and OnRecordAfterDeleteSuccess for the given record, like a successful
+// App.Delete.
+func (f *FakeHooks) TriggerRecordDelete(record *core.Record) error {
+  event := f.newRecordEvent(record, core.ModelEventTypeDelete)
+  if err := f.onRecordDelete.Trigger(event); err != nil {
+    return err
+  }
+
+  return f.onRecordAfterDeleteSuccess.Trigger(f.newRecordEvent(record, core.ModelEventTypeDelete))
+}
+
+// TriggerMailerSend triggers OnMailerSend for the given message.
+func (f *FakeHooks) TriggerMailerSend(message *mailer.Message) error {
+  event := &core.MailerEvent{App: f.App}
+  event.Message = message
+
+  return f.onMailerSend.Trigger(event)
+}
+
+// TriggerRecordAuthRequest triggers OnRecordAuthRequest for the given auth record, like a successful authentication
+// with the given method, e.g. "password". The returned recorder holds the response written by the handlers.
+func (f *FakeHooks) TriggerRecordAuthRequest(record *core.Record, authMethod string) (*httptest.ResponseRecorder, error) {
+  recorder := httptest.NewRecorder()
+
+  event := &core.RecordAuthRequestEvent{RequestEvent: &core.RequestEvent{App: f.App}}
+  event.Request = httptest.NewRequest(http.MethodPost, "/api/collections/"+record.Collection().Name+"/auth-with-"+authMethod, nil)
+  event.Response = recorder
+  event.Collection = record.Collection()
+  event.Record = record
+  event.AuthMethod = authMethod
+
+  return recorder, f.onRecordAuthRequest.Trigger(event)
+}
+
+func (f *FakeHooks) newRecordEvent(record *core.Record, eventType string) *core.RecordEvent {
+  event := &core.RecordEvent{App: f.App, Context: context.Background(), Type: eventType}
+  event.Record = record
+
+  return event
+}
@@ -0,0 +1,191 @@
+package pocketframeworktest
+
+import (
+  "bytes"
+  "encoding/json"
+  "io"
+  "net/http"
+  "net/http/httptest"
+  "reflect"
+  "strings"
+  "testing"
+
+  "github.com/pocketbase/pocketbase/core"
+)
+
+// Request is a request against the router of a TestApp. Requests are sent as guest unless AsRecord or AsSuperuser
+// is used.
+type Request struct {
+  app     *TestApp
+  method  string
+  path    string
+  headers http.Header
+  body    io.Reader
+  err     error
+}
+
+// Request starts a new request to the given path, including the API prefix, e.g. "/api/invoices".
+func (a *TestApp) Request(method string, path string) *Request {
+  return &Request{app: a, method: method, path: path, headers: http.Header{}}
+}
+
+// AsGuest sends the request without authorization.
+func (r *Request) AsGuest() *Request {
+  r.headers.Del("Authorization")
+  return r
+}
+
+// AsRecord sends the request authorized as the given auth record.
+func (r *Request) AsRecord(record *core.Record) *Request {
+  token, err := record.NewAuthToken()
+  if err != nil {
+    r.err = err
+    return r
+  }
+
+  r.headers.Set("Authorization", token)
+  return r
+}
+
+// AsSuperuser sends the request authorized as the superuser of the app, see TestApp.Superuser.
+func (r *Request) AsSuperuser() *Request {
+  return r.AsRecord(r.app.Superuser())
+}
+
+// WithHeader sets a request header.
+func (r *Request) WithHeader(key string, value string) *Request {
+  r.headers.Set(key, value)
+  return r
+}
+
+// WithJSON sends the given value as JSON body.
+func (r *Request) WithJSON(body any) *Request {
+  data, err := json.Marshal(body)
+  if err != nil {
+    r.err = err
+    return r
+  }
+
+  r.body = bytes.NewReader(data)
+  r.headers.Set("Content-Type", "application/json")
+  return r
+}
+
+// WithBody sends the given body as is.
+func (r *Request) WithBody(body io.Reader) *Request {
+  r.body = body
+  return r
+}
+
+// Do sends the request. The hooks triggered while handling it are available through Response.EventCalls.
+func (r *Request) Do() *Response {
+  t := r.app.t
+  t.Helper()
+
+  if r.err != nil {
+    t.Fatalf("failed to build the request %s %s: %v", r.method, r.path, r.err)
+  }
+
+  req := httptest.NewRequest(r.method, r.path, r.body)
+  for key, values := range r.headers {
+    req.Header[key] = values
+  }
+
+  r.app.ResetEventCalls()
+
+  recorder := httptest.NewRecorder()
+  r.app.handler.ServeHTTP(recorder, req)
+
+  return &Response{
+    t:          t,
+    request:    r.method + " " + r.path,
+    Status:     recorder.Code,
+    Header:     recorder.Header(),
+    Body:       recorder.Body.Bytes(),
+    eventCalls: r.app.EventCalls(),
+  }
+}
+
+// Response is the recorded response of a Request.
+type Response struct {
+  Status int
+  Header http.Header
+  Body   []byte
+
+  t          testing.TB
+  request    string
+  eventCalls map[string]int
+}
+
+// EventCalls returns how often each app hook has been triggered while handling the request.
+func (r *Response) EventCalls() map[string]int {
+  return r.eventCalls
+}
+
+// AssertStatus fails the test if the response has a different status.
+func (r *Response) AssertStatus(status int) *Response {
+  r.t.Helper()
+
+  if r.Status != status {
+    r.t.Errorf("%s: expected status %d, got %d with body %s", r.request, status, r.Status, r.Body)
+  }
+  return r
+}
+
+// AssertJSON fails the test if the body is not equal to the JSON encoding of expected, ignoring formatting and the
+// order of object keys.
+func (r *Response) AssertJSON(expected any) *Response {
+  r.t.Helper()
+
+  expectedData, err := json.Marshal(expected)
+  if err != nil {
+    r.t.Fatalf("%s: failed to encode the expected body: %v", r.request, err)
+  }
+
+  var want, got any
+  if err := json.Unmarshal(expectedData, &want); err != nil {
+    r.t.Fatalf("%s: failed to decode the expected body: %v", r.request, err)
+  }
+  if err := json.Unmarshal(r.Body, &got); err != nil {
+    r.t.Errorf("%s: expected a JSON body, got %s", r.request, r.Body)
+    return r
+  }
+
+  if !reflect.DeepEqual(want, got) {
+    r.t.Errorf("%s: expected body %s, got %s", r.request, expectedData, r.Body)
+  }
+  return r
+}
+
+// AssertBodyContains fails the test if the body does not contain all given parts.
+func (r *Response) AssertBodyContains(parts ...string) *Response {
+  r.t.Helper()
+
+  for _, part := range parts {
+    if !strings.Contains(string(r.Body), part) {
+      r.t.Errorf("%s: expected the body to contain %q, got %s", r.request, part, r.Body)
+    }
+  }
+  return r
+}
+
+// AssertHookFired fails the test if the hook with the given name, e.g. "OnRecordCreate", was not triggered exactly
+// times times while handling the request.
+func (r *Response) AssertHookFired(name string, times int) *Response {
+  r.t.Helper()
+
+  if r.eventCalls[name] != times {
+    r.t.Errorf("%s: expected %s to be triggered %d times, got %d", r.request, name, times, r.eventCalls[name])
+  }
+  return r
+}
+
+// DecodeJSON decodes the body into dst and fails the test if it is not valid JSON.
+func (r *Response) DecodeJSON(dst any) *Response {
+  r.t.Helper()
+
+  if err := json.Unmarshal(r.Body, dst); err != nil {
+    r.t.Fatalf("%s: failed to decode the body %s: %v", r.request, r.Body, err)
+  }
+  return r
+}
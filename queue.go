@@ -0,0 +1,492 @@
+package pocketframework
+
+import (
+  "context"
+  "database/sql"
+  "encoding/json"
+  "errors"
+  "fmt"
+  "strconv"
+  "strings"
+  "sync"
+  "time"
+
+  "github.com/pocketbase/dbx"
+  "github.com/pocketbase/pocketbase/core"
+  "github.com/pocketbase/pocketbase/tools/types"
+)
+
+// QueueCollection is the system collection which stores the jobs of the queue. Its run_at field is the unix time in
+// microseconds at which a job becomes due.
+const QueueCollection = "_pocketframework_queue"
+
+const (
+  DefaultQueueWorkers      = 4
+  DefaultQueuePollInterval = time.Second
+  DefaultQueueMaxAttempts  = 5
+  DefaultQueueBackoffBase  = 10 * time.Second
+  DefaultQueueBackoffMax   = time.Hour
+)
+
+// QueueJobState is the state of a queued job. Jobs are deleted once they have been handled successfully.
+type QueueJobState string
+
+const (
+  QueueJobPending QueueJobState = "pending"
+  QueueJobRunning QueueJobState = "running"
+
+  // QueueJobDead is the state of jobs which failed too often. They are kept until they are retried with
+  // Queue.Retry.
+  QueueJobDead QueueJobState = "dead"
+)
+
+// QueueJob is a job passed to a QueueHandler.
+type QueueJob struct {
+  Id          string
+  Type        string
+  Payload     json.RawMessage
+  Attempts    int
+  MaxAttempts int
+}
+
+// QueueHandler handles the jobs of a single type.
+type QueueHandler struct {
+  // Type identifies the jobs of this handler, e.g. "invoices.send". It must be unique across all modules.
+  Type string
+
+  // MaxAttempts is the number of attempts after which a failing job becomes dead. Defaults to
+  // DefaultQueueMaxAttempts.
+  MaxAttempts int
+
+  // Handle handles a single job. ctx is cancelled if the job does not finish before the queue has been drained.
+  Handle func(ctx context.Context, job QueueJob) error
+}
+
+// NewQueueHandler creates a handler for jobs with a JSON payload of type T.
+func NewQueueHandler[T any](jobType string, fn func(ctx context.Context, payload T) error) QueueHandler {
+  return QueueHandler{
+    Type: jobType,
+    Handle: func(ctx context.Context, job QueueJob) error {
+      var payload T
+      if err := json.Unmarshal(job.Payload, &payload); err != nil {
+        return fmt.Errorf("failed to decode the payload of job %q: %w", job.Id, err)
+      }
+      return fn(ctx, payload)
+    },
+  }
+}
+
+// EnqueueOption configures a single enqueued job.
+type EnqueueOption func(o *enqueueOptions)
+
+type enqueueOptions struct {
+  app         core.App
+  runAt       time.Time
+  maxAttempts int
+}
+
+// EnqueueAt delays the job until the given time.
+func EnqueueAt(t time.Time) EnqueueOption {
+  return func(o *enqueueOptions) {
+    o.runAt = t
+  }
+}
+
+// EnqueueAfter delays the job by the given duration.
+func EnqueueAfter(d time.Duration) EnqueueOption {
+  return func(o *enqueueOptions) {
+    o.runAt = time.Now().Add(d)
+  }
+}
+
+// EnqueueMaxAttempts overrides the max attempts of the handler for this job.
+func EnqueueMaxAttempts(attempts int) EnqueueOption {
+  return func(o *enqueueOptions) {
+    o.maxAttempts = attempts
+  }
+}
+
+// EnqueueWithApp stores the job through the given app, e.g. the txApp of a transaction, so the job is only queued if
+// the transaction commits.
+func EnqueueWithApp(app core.App) EnqueueOption {
+  return func(o *enqueueOptions) {
+    o.app = app
+  }
+}
+
+// Queue is a persistent job queue. Jobs survive restarts and are handled by the handlers of the modules implementing
+// ModuleWithQueueHandlers. The registry provides it as a service, use Resolve[*Queue] to access it.
+type Queue struct {
+  app          core.App
+  workers      int
+  pollInterval time.Duration
+  backoffBase  time.Duration
+  backoffMax   time.Duration
+
+  handlers map[string]queueHandler
+  enabled  func(node *moduleNode) bool
+  wake     chan struct{}
+
+  mu      sync.Mutex
+  started bool
+  wg      sync.WaitGroup
+  stop    chan struct{}
+  cancel  context.CancelFunc
+}
+
+type queueHandler struct {
+  handler QueueHandler
+  node    *moduleNode
+}
+
+type queueRow struct {
+  Id          string `db:"id"`
+  Type        string `db:"type"`
+  Payload     string `db:"payload"`
+  Attempts    int    `db:"attempts"`
+  MaxAttempts int    `db:"max_attempts"`
+}
+
+func newQueue(app core.App) *Queue {
+  return &Queue{
+    app:          app,
+    workers:      DefaultQueueWorkers,
+    pollInterval: DefaultQueuePollInterval,
+    backoffBase:  DefaultQueueBackoffBase,
+    backoffMax:   DefaultQueueBackoffMax,
+    handlers:     map[string]queueHandler{},
+    wake:         make(chan struct{}, 1),
+  }
+}
+
+// WithQueueWorkers sets the number of jobs handled concurrently. Defaults to DefaultQueueWorkers.
+func WithQueueWorkers(workers int) RegistryOption {
+  return func(m *ModuleRegistry) {
+    m.queue.workers = workers
+  }
+}
+
+// WithQueuePollInterval sets how often idle workers look for due jobs. Defaults to DefaultQueuePollInterval.
+func WithQueuePollInterval(interval time.Duration) RegistryOption {
+  return func(m *ModuleRegistry) {
+    m.queue.pollInterval = interval
+  }
+}
+
+// WithQueueBackoff sets the delay before the first retry of a failed job, which doubles with every further attempt
+// up to max. Defaults to DefaultQueueBackoffBase and DefaultQueueBackoffMax.
+func WithQueueBackoff(base time.Duration, max time.Duration) RegistryOption {
+  return func(m *ModuleRegistry) {
+    m.queue.backoffBase = base
+    m.queue.backoffMax = max
+  }
+}
+
+// Queue returns the job queue of the registry.
+func (m *ModuleRegistry) Queue() *Queue {
+  return m.queue
+}
+
+// Enqueue adds a job with the given type and JSON payload to the queue and returns its id. It fails if no module
+// handles the job type.
+func Enqueue[T any](q *Queue, jobType string, payload T, opts ...EnqueueOption) (string, error) {
+  data, err := json.Marshal(payload)
+  if err != nil {
+    return "", fmt.Errorf("failed to encode the payload of a %q job: %w", jobType, err)
+  }
+
+  if _, ok := q.handlers[jobType]; !ok {
+    return "", fmt.Errorf("failed to enqueue a %q job: no module handles this job type", jobType)
+  }
+
+  options := enqueueOptions{app: q.app, runAt: time.Now()}
+  for _, opt := range opts {
+    opt(&options)
+  }
+
+  collection, err := options.app.FindCachedCollectionByNameOrId(QueueCollection)
+  if err != nil {
+    return "", fmt.Errorf("failed to enqueue a %q job: %w", jobType, err)
+  }
+
+  record := core.NewRecord(collection)
+  record.Set("type", jobType)
+  record.Set("payload", types.JSONRaw(data))
+  record.Set("state", string(QueueJobPending))
+  record.Set("max_attempts", options.maxAttempts)
+  record.Set("run_at", options.runAt.UnixMicro())
+  if err := options.app.Save(record); err != nil {
+    return "", fmt.Errorf("failed to enqueue a %q job: %w", jobType, err)
+  }
+
+  select {
+  case q.wake <- struct{}{}:
+  default:
+  }
+
+  return record.Id, nil
+}
+
+// Retry makes a dead job pending again and resets its attempts.
+func (q *Queue) Retry(id string) error {
+  result, err := q.app.NonconcurrentDB().Update(
+    QueueCollection, dbx.Params{
+      "state":    QueueJobPending,
+      "attempts": 0,
+      "run_at":   time.Now().UnixMicro(),
+      "updated":  types.NowDateTime().String(),
+    }, dbx.HashExp{"id": id, "state": QueueJobDead},
+  ).Execute()
+  if err != nil {
+    return fmt.Errorf("failed to retry job %q: %w", id, err)
+  }
+
+  if affected, _ := result.RowsAffected(); affected == 0 {
+    return fmt.Errorf("there is no dead job %q", id)
+  }
+
+  return nil
+}
+
+// Stats returns the number of jobs in each state.
+func (q *Queue) Stats() (map[QueueJobState]int, error) {
+  rows := []struct {
+    State QueueJobState `db:"state"`
+    Count int           `db:"count"`
+  }{}
+
+  err := q.app.DB().NewQuery("SELECT [[state]], COUNT(*) AS [[count]] FROM {{" + QueueCollection + "}} GROUP BY [[state]]").All(&rows)
+  if err != nil {
+    return nil, fmt.Errorf("failed to count the queued jobs: %w", err)
+  }
+
+  stats := map[QueueJobState]int{QueueJobPending: 0, QueueJobRunning: 0, QueueJobDead: 0}
+  for _, row := range rows {
+    stats[row.State] = row.Count
+  }
+
+  return stats, nil
+}
+
+// collectQueueHandlers validates the queue handlers of all modules.
+func (m *ModuleRegistry) collectQueueHandlers() error {
+  m.queue.enabled = m.nodeEnabled
+
+  for _, root := range m.roots {
+    err := root.walk(
+      func(node *moduleNode) error {
+        moduleWithQueueHandlers, ok := node.module.(ModuleWithQueueHandlers)
+        if !ok {
+          return nil
+        }
+
+        for _, handler := range moduleWithQueueHandlers.QueueHandlers() {
+          if handler.Type == "" || handler.Handle == nil {
+            return fmt.Errorf("module %q has a queue handler without type or handle function", node.path)
+          }
+          if existing, ok := m.queue.handlers[handler.Type]; ok {
+            return fmt.Errorf("modules %q and %q both handle the queue job type %q", existing.node.path, node.path, handler.Type)
+          }
+
+          m.queue.handlers[handler.Type] = queueHandler{handler: handler, node: node}
+        }
+        return nil
+      },
+    )
+    if err != nil {
+      return err
+    }
+  }
+
+  return nil
+}
+
+// start resets the jobs interrupted by a previous shutdown and starts the workers. It does nothing if the workers
+// are already running.
+func (q *Queue) start(ctx context.Context) error {
+  q.mu.Lock()
+  defer q.mu.Unlock()
+
+  if q.started {
+    return nil
+  }
+
+  _, err := q.app.NonconcurrentDB().Update(
+    QueueCollection, dbx.Params{"state": QueueJobPending}, dbx.HashExp{"state": QueueJobRunning},
+  ).Execute()
+  if err != nil {
+    return fmt.Errorf("failed to reset the interrupted jobs: %w", err)
+  }
+
+  ctx, q.cancel = context.WithCancel(ctx)
+  q.stop = make(chan struct{})
+  q.started = true
+
+  for i := 0; i < q.workers; i++ {
+    q.wg.Add(1)
+    go func(stop <-chan struct{}) {
+      defer q.wg.Done()
+      q.work(ctx, stop)
+    }(q.stop)
+  }
+
+  return nil
+}
+
+// drain stops claiming new jobs and waits for the running ones. Jobs still running after the timeout are cancelled
+// and handled again after the next start. It does nothing if the workers are not running.
+func (q *Queue) drain(timeout time.Duration) error {
+  q.mu.Lock()
+  if !q.started {
+    q.mu.Unlock()
+    return nil
+  }
+  stop, cancel := q.stop, q.cancel
+  q.started, q.stop, q.cancel = false, nil, nil
+  q.mu.Unlock()
+
+  close(stop)
+  defer cancel()
+
+  done := make(chan struct{})
+  go func() {
+    q.wg.Wait()
+    close(done)
+  }()
+
+  select {
+  case <-done:
+    return nil
+  case <-time.After(timeout):
+    return fmt.Errorf("queue did not drain within %s", timeout)
+  }
+}
+
+func (q *Queue) work(ctx context.Context, stop <-chan struct{}) {
+  for {
+    select {
+    case <-stop:
+      return
+    default:
+    }
+
+    job, handler, err := q.claim()
+    if err != nil {
+      q.app.Logger().Error("Failed to claim a queued job", "error", err)
+    }
+
+    if job == nil {
+      select {
+      case <-stop:
+        return
+      case <-q.wake:
+      case <-time.After(q.pollInterval):
+      }
+      continue
+    }
+
+    q.process(ctx, job, handler)
+  }
+}
+
+// claim marks the next due job of an enabled module as running. It returns nil if there is none.
+func (q *Queue) claim() (*QueueJob, QueueHandler, error) {
+  params := dbx.Params{"now": time.Now().UnixMicro(), "updated": types.NowDateTime().String()}
+
+  placeholders := []string{}
+  for jobType, handler := range q.handlers {
+    if q.enabled != nil && !q.enabled(handler.node) {
+      continue
+    }
+
+    name := "t" + strconv.Itoa(len(placeholders))
+    params[name] = jobType
+    placeholders = append(placeholders, "{:"+name+"}")
+  }
+  if len(placeholders) == 0 {
+    return nil, QueueHandler{}, nil
+  }
+
+  row := queueRow{}
+  err := q.app.NonconcurrentDB().NewQuery(
+    "UPDATE {{" + QueueCollection + "}} SET [[state]] = 'running', [[attempts]] = [[attempts]] + 1, [[updated]] = {:updated} " +
+      "WHERE [[id]] = (" +
+      "SELECT [[id]] FROM {{" + QueueCollection + "}} " +
+      "WHERE [[state]] = 'pending' AND [[run_at]] <= {:now} AND [[type]] IN (" + strings.Join(placeholders, ", ") + ") " +
+      "ORDER BY [[run_at]], [[created]] LIMIT 1) " +
+      "RETURNING [[id]], [[type]], [[payload]], [[attempts]], [[max_attempts]]",
+  ).Bind(params).One(&row)
+  if errors.Is(err, sql.ErrNoRows) {
+    return nil, QueueHandler{}, nil
+  }
+  if err != nil {
+    return nil, QueueHandler{}, err
+  }
+
+  handler := q.handlers[row.Type].handler
+
+  job := &QueueJob{
+    Id:          row.Id,
+    Type:        row.Type,
+    Payload:     json.RawMessage(row.Payload),
+    Attempts:    row.Attempts,
+    MaxAttempts: row.MaxAttempts,
+  }
+  if job.MaxAttempts <= 0 {
+    job.MaxAttempts = handler.MaxAttempts
+  }
+  if job.MaxAttempts <= 0 {
+    job.MaxAttempts = DefaultQueueMaxAttempts
+  }
+
+  return job, handler, nil
+}
+
+// process handles a claimed job and either deletes it, schedules a retry or marks it as dead.
+func (q *Queue) process(ctx context.Context, job *QueueJob, handler QueueHandler) {
+  err := runQueueHandler(ctx, handler, *job)
+  if err == nil {
+    _, err = q.app.NonconcurrentDB().Delete(QueueCollection, dbx.HashExp{"id": job.Id}).Execute()
+    if err != nil {
+      q.app.Logger().Error("Failed to delete a handled job", "job", job.Id, "type", job.Type, "error", err)
+    }
+    return
+  }
+
+  params := dbx.Params{
+    "state":      QueueJobPending,
+    "run_at":     time.Now().Add(q.backoff(job.Attempts)).UnixMicro(),
+    "last_error": err.Error(),
+    "updated":    types.NowDateTime().String(),
+  }
+  if job.Attempts >= job.MaxAttempts {
+    params["state"] = QueueJobDead
+    q.app.Logger().Error("Queued job failed for the last time", "job", job.Id, "type", job.Type, "attempts", job.Attempts, "error", err)
+  } else {
+    q.app.Logger().Warn("Queued job failed", "job", job.Id, "type", job.Type, "attempts", job.Attempts, "error", err)
+  }
+
+  if _, err := q.app.NonconcurrentDB().Update(QueueCollection, params, dbx.HashExp{"id": job.Id}).Execute(); err != nil {
+    q.app.Logger().Error("Failed to update a failed job", "job", job.Id, "type", job.Type, "error", err)
+  }
+}
+
+// backoff returns the delay before the next attempt, doubling with every attempt.
+func (q *Queue) backoff(attempts int) time.Duration {
+  delay := q.backoffBase
+  for i := 1; i < attempts && delay < q.backoffMax; i++ {
+    delay *= 2
+  }
+
+  return min(delay, q.backoffMax)
+}
+
+func runQueueHandler(ctx context.Context, handler QueueHandler, job QueueJob) (err error) {
+  defer func() {
+    if r := recover(); r != nil {
+      err = fmt.Errorf("queue handler panicked: %v", r)
+    }
+  }()
+
+  return handler.Handle(ctx, job)
+}
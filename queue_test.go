@@ -0,0 +1,179 @@
+package pocketframework
+
+import (
+  "context"
+  "errors"
+  "testing"
+  "time"
+
+  "github.com/pocketbase/dbx"
+  "github.com/pocketbase/pocketbase/tools/types"
+)
+
+// queueModule is a test module with a single queue handler which always fails.
+type queueModule struct {
+  testModule
+}
+
+func (q *queueModule) QueueHandlers() []QueueHandler {
+  return []QueueHandler{
+    {
+      Type:        "invoices.send",
+      MaxAttempts: 2,
+      Handle: func(ctx context.Context, job QueueJob) error {
+        return errors.New("mail server unavailable")
+      },
+    },
+  }
+}
+
+func TestQueueBackoff(t *testing.T) {
+  q := &Queue{backoffBase: 10 * time.Second, backoffMax: time.Minute}
+
+  tests := []struct {
+    attempts int
+    delay    time.Duration
+  }{
+    {attempts: 1, delay: 10 * time.Second},
+    {attempts: 2, delay: 20 * time.Second},
+    {attempts: 3, delay: 40 * time.Second},
+    {attempts: 4, delay: time.Minute},
+    {attempts: 100, delay: time.Minute},
+  }
+
+  for _, tt := range tests {
+    if delay := q.backoff(tt.attempts); delay != tt.delay {
+      t.Errorf("backoff(%d) = %s, want %s", tt.attempts, delay, tt.delay)
+    }
+  }
+}
+
+func TestQueueRetry(t *testing.T) {
+  registry, app := newTestRegistry(t, &queueModule{testModule: testModule{name: "invoices"}})
+  q := registry.Queue()
+
+  id, err := Enqueue(q, "invoices.send", map[string]string{"invoice": "1"})
+  if err != nil {
+    t.Fatalf("Enqueue() failed: %v", err)
+  }
+
+  load := func() (QueueJobState, int, time.Time) {
+    t.Helper()
+
+    row := struct {
+      State    QueueJobState `db:"state"`
+      Attempts int           `db:"attempts"`
+      RunAt    int64         `db:"run_at"`
+    }{}
+    err := app.DB().Select("state", "attempts", "run_at").From(QueueCollection).Where(dbx.HashExp{"id": id}).One(&row)
+    if err != nil {
+      t.Fatalf("failed to load job %q: %v", id, err)
+    }
+
+    return row.State, row.Attempts, time.UnixMicro(row.RunAt)
+  }
+
+  process := func() {
+    t.Helper()
+
+    // make the job due, skipping the backoff
+    _, err := app.DB().Update(QueueCollection, dbx.Params{"run_at": 0}, dbx.HashExp{"id": id}).Execute()
+    if err != nil {
+      t.Fatalf("failed to make job %q due: %v", id, err)
+    }
+
+    job, handler, err := q.claim()
+    if err != nil || job == nil {
+      t.Fatalf("claim() = %v, %v", job, err)
+    }
+    q.process(context.Background(), job, handler)
+  }
+
+  process()
+  if state, attempts, runAt := load(); state != QueueJobPending || attempts != 1 || runAt.Before(time.Now().Add(DefaultQueueBackoffBase/2)) {
+    t.Errorf("after the first failure: state %q, attempts %d, run at %s", state, attempts, runAt)
+  }
+
+  process()
+  if state, attempts, _ := load(); state != QueueJobDead || attempts != 2 {
+    t.Errorf("after the last failure: state %q, attempts %d", state, attempts)
+  }
+
+  if job, _, err := q.claim(); err != nil || job != nil {
+    t.Errorf("claim() of a dead job = %v, %v", job, err)
+  }
+
+  if err := q.Retry(id); err != nil {
+    t.Fatalf("Retry() failed: %v", err)
+  }
+  if state, attempts, _ := load(); state != QueueJobPending || attempts != 0 {
+    t.Errorf("after the retry: state %q, attempts %d", state, attempts)
+  }
+
+  if err := q.Retry(id); err == nil {
+    t.Errorf("Retry() of a pending job succeeded")
+  }
+}
+
+func TestQueueStartDrain(t *testing.T) {
+  registry, _ := newTestRegistry(t)
+  q := registry.Queue()
+
+  // serving more than once must not leak workers and terminating more than once must not panic
+  for i := 0; i < 2; i++ {
+    if err := q.start(context.Background()); err != nil {
+      t.Fatalf("start() failed: %v", err)
+    }
+    if err := q.start(context.Background()); err != nil {
+      t.Fatalf("start() of a started queue failed: %v", err)
+    }
+
+    if err := q.drain(time.Second); err != nil {
+      t.Fatalf("drain() failed: %v", err)
+    }
+    if err := q.drain(time.Second); err != nil {
+      t.Fatalf("drain() of a drained queue failed: %v", err)
+    }
+  }
+}
+
+func TestEnqueue(t *testing.T) {
+  registry, app := newTestRegistry(t, &queueModule{testModule: testModule{name: "invoices"}})
+  q := registry.Queue()
+
+  collection, err := app.FindCollectionByNameOrId(QueueCollection)
+  if err != nil {
+    t.Fatalf("failed to find the queue collection: %v", err)
+  }
+  if !collection.System {
+    t.Errorf("expected the queue collection to be a system collection")
+  }
+
+  runAt := time.Now().Add(time.Hour)
+  id, err := Enqueue(q, "invoices.send", map[string]string{"invoice": "1"}, EnqueueAt(runAt), EnqueueMaxAttempts(3))
+  if err != nil {
+    t.Fatalf("Enqueue() failed: %v", err)
+  }
+
+  record, err := app.FindRecordById(QueueCollection, id)
+  if err != nil {
+    t.Fatalf("failed to find job %q: %v", id, err)
+  }
+  if record.GetString("type") != "invoices.send" || record.GetString("state") != string(QueueJobPending) ||
+    record.GetInt("max_attempts") != 3 || int64(record.GetFloat("run_at")) != runAt.UnixMicro() ||
+    string(record.Get("payload").(types.JSONRaw)) != `{"invoice":"1"}` {
+    t.Errorf("unexpected job %v", record.FieldsData())
+  }
+
+  if _, err := Enqueue(q, "invoices.archive", struct{}{}); err == nil || err.Error() != `failed to enqueue a "invoices.archive" job: no module handles this job type` {
+    t.Errorf("expected an error for a job type without handler, got %v", err)
+  }
+
+  stats, err := q.Stats()
+  if err != nil {
+    t.Fatalf("Stats() failed: %v", err)
+  }
+  if stats[QueueJobPending] != 1 {
+    t.Errorf("expected one pending job, got %v", stats)
+  }
+}
@@ -0,0 +1,97 @@
+package pocketframework
+
+import (
+  "net/http"
+  "testing"
+
+  "github.com/pocketbase/pocketbase/apis"
+  "github.com/pocketbase/pocketbase/core"
+  _ "github.com/pocketbase/pocketbase/migrations"
+)
+
+// newTestRegistry boots a fresh app with a temporary data dir and a module registry holding the given modules.
+func newTestRegistry(t *testing.T, modules ...Module) (*ModuleRegistry, core.App) {
+  t.Helper()
+
+  return newTestRegistryWithOptions(t, nil, modules...)
+}
+
+// newTestRegistryWithOptions is like newTestRegistry but configures the registry with the given options.
+func newTestRegistryWithOptions(t *testing.T, opts []RegistryOption, modules ...Module) (*ModuleRegistry, core.App) {
+  t.Helper()
+
+  app := core.NewBaseApp(core.BaseAppConfig{DataDir: t.TempDir()})
+
+  registry := NewModuleRegistry(app, "/api", opts...)
+  for _, module := range modules {
+    registry.Register(module)
+  }
+
+  if err := registry.Init(); err != nil {
+    t.Fatalf("Init() failed: %v", err)
+  }
+
+  if err := app.Bootstrap(); err != nil {
+    t.Fatalf("Bootstrap() failed: %v", err)
+  }
+  t.Cleanup(
+    func() {
+      _ = app.ResetBootstrapState()
+    },
+  )
+
+  return registry, app
+}
+
+// serveTestRegistry mounts the routes of the registry on the router of the app, like serving the app does, and
+// returns the resulting handler.
+func serveTestRegistry(t *testing.T, registry *ModuleRegistry, app core.App) http.Handler {
+  t.Helper()
+
+  r, err := apis.NewRouter(app)
+  if err != nil {
+    t.Fatalf("failed to create the router: %v", err)
+  }
+
+  var handler http.Handler
+  err = app.OnServe().Trigger(
+    &core.ServeEvent{App: app, Router: r}, func(e *core.ServeEvent) error {
+      handler, err = e.Router.BuildMux()
+      return err
+    },
+  )
+  if err != nil {
+    t.Fatalf("failed to mount the routes: %v", err)
+  }
+  t.Cleanup(
+    func() {
+      _ = registry.queue.drain(registry.stopTimeout)
+    },
+  )
+
+  return handler
+}
+
+// newTestSuperuserToken creates a superuser in the app and returns an auth token for it.
+func newTestSuperuserToken(t *testing.T, app core.App) string {
+  t.Helper()
+
+  superusers, err := app.FindCollectionByNameOrId(core.CollectionNameSuperusers)
+  if err != nil {
+    t.Fatalf("failed to find the superusers collection: %v", err)
+  }
+
+  superuser := core.NewRecord(superusers)
+  superuser.SetEmail("superuser@example.com")
+  superuser.SetPassword("1234567890")
+  if err := app.Save(superuser); err != nil {
+    t.Fatalf("failed to create the superuser: %v", err)
+  }
+
+  token, err := superuser.NewAuthToken()
+  if err != nil {
+    t.Fatalf("failed to create the auth token: %v", err)
+  }
+
+  return token
+}
@@ -0,0 +1,188 @@
+package pocketframework
+
+import (
+  "fmt"
+  "net/http"
+  "sort"
+  "sync"
+
+  "github.com/pocketbase/pocketbase/core"
+  "github.com/pocketbase/pocketbase/tools/router"
+)
+
+// MethodAny is the method recorded for routes which match any method.
+const MethodAny = "ANY"
+
+// RouteInfo describes a route mounted by a module.
+type RouteInfo struct {
+  Method  string `json:"method"`
+  Path    string `json:"path"`
+  Group   string `json:"group"`
+  Module  string `json:"module"`
+  Handler string `json:"handler"`
+
+  Doc *RouteDoc `json:"-"`
+}
+
+// RouteDoc documents a route in the generated OpenAPI document.
+type RouteDoc struct {
+  OperationId string
+  Summary     string
+  Description string
+  Tags        []string
+  Deprecated  bool
+
+  // Request is an example value of the request, e.g. CreateInvoiceRequest{}. Only its type is used. Fields with a
+  // "query" tag are documented as query parameters, see Handle.
+  Request any
+
+  // Response is an example value of the response body, e.g. []Invoice{}. Only its type is used.
+  Response any
+
+  // ResponseStatus is the status of a successful response. Defaults to 200.
+  ResponseStatus int
+}
+
+type routeTable struct {
+  mu      sync.RWMutex
+  routes  []*Route
+  metrics *Metrics
+  tracer  *Tracer
+}
+
+func (t *routeTable) add(route *Route) {
+  t.mu.Lock()
+  defer t.mu.Unlock()
+
+  t.routes = append(t.routes, route)
+}
+
+func (t *routeTable) infos() []RouteInfo {
+  t.mu.RLock()
+  defer t.mu.RUnlock()
+
+  infos := make([]RouteInfo, 0, len(t.routes))
+  for _, route := range t.routes {
+    infos = append(infos, route.info)
+  }
+
+  sort.SliceStable(
+    infos, func(i, j int) bool {
+      if infos[i].Path != infos[j].Path {
+        return infos[i].Path < infos[j].Path
+      }
+      return infos[i].Method < infos[j].Method
+    },
+  )
+
+  return infos
+}
+
+// validate rejects routes with the same method and path, regardless of their group. Path parameters are compared
+// by position only, so "/invoices/{id}" conflicts with "/invoices/{invoiceId}". Routes matching any method conflict
+// with every other route of the same path.
+func (t *routeTable) validate() error {
+  t.mu.RLock()
+  defer t.mu.RUnlock()
+
+  routesByPath := map[string][]RouteInfo{}
+  for _, route := range t.routes {
+    path := pathParamRegex.ReplaceAllString(route.info.Path, "{$2}")
+
+    for _, existing := range routesByPath[path] {
+      if existing.Method == route.info.Method || existing.Method == MethodAny || route.info.Method == MethodAny {
+        return fmt.Errorf(
+          "route %s %s of module %q (group %q) conflicts with route %s %s of module %q (group %q)",
+          route.info.Method, route.info.Path, route.info.Module, route.info.Group,
+          existing.Method, existing.Path, existing.Module, existing.Group,
+        )
+      }
+    }
+
+    routesByPath[path] = append(routesByPath[path], route.info)
+  }
+
+  return nil
+}
+
+// Routes returns every route mounted by the registered modules, sorted by path and method.
+//
+// If the app is not serving yet, the routes are collected by mounting the modules on a detached router, so
+// RegisterRoutes should not have side effects besides registering routes.
+func (m *ModuleRegistry) Routes() ([]RouteInfo, error) {
+  m.mu.RLock()
+  table := m.routes
+  m.mu.RUnlock()
+
+  if table == nil {
+    detached := router.NewRouter(
+      func(w http.ResponseWriter, r *http.Request) (*core.RequestEvent, router.EventCleanupFunc) {
+        event := new(core.RequestEvent)
+        event.App = m.app
+        event.Response = w
+        event.Request = r
+
+        return event, nil
+      },
+    )
+
+    var err error
+    if table, err = m.buildRoutes(detached); err != nil {
+      return nil, err
+    }
+  }
+
+  return table.infos(), nil
+}
+
+// mountRoutes mounts the routes of all modules on the app router and keeps the resulting route table.
+func (m *ModuleRegistry) mountRoutes(r *router.Router[*core.RequestEvent]) error {
+  table, err := m.buildRoutes(r)
+  if err != nil {
+    return err
+  }
+
+  m.mu.Lock()
+  m.routes = table
+  m.mu.Unlock()
+
+  return nil
+}
+
+func (m *ModuleRegistry) buildRoutes(r *router.Router[*core.RequestEvent]) (*routeTable, error) {
+  table := &routeTable{metrics: m.metrics, tracer: m.tracer}
+
+  groups := []*RouterGroup{}
+  for _, definition := range m.groupDefinitions() {
+    group := r.Group(m.apiPrefix)
+    group.Bind(definition.Middlewares...)
+
+    groups = append(groups, newRouterGroup(group, definition.Name, m.apiPrefix, table))
+  }
+  baseGroups := newRouterGroups(groups)
+  m.registerFrameworkRoutes(baseGroups)
+
+  // modules are served in dependency order, each one within the groups of its parent
+  moduleGroups := map[*moduleNode]RouterGroups{}
+  err := m.forEachModule(
+    func(node *moduleNode) error {
+      parentGroups := baseGroups
+      if node.parent != nil {
+        parentGroups = moduleGroups[node.parent]
+      }
+
+      groups, err := m.serveModule(node, parentGroups)
+      moduleGroups[node] = groups
+      return err
+    },
+  )
+  if err != nil {
+    return nil, err
+  }
+
+  if err := table.validate(); err != nil {
+    return nil, err
+  }
+
+  return table, nil
+}
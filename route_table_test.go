@@ -0,0 +1,123 @@
+package pocketframework
+
+import (
+  "strings"
+  "testing"
+
+  "github.com/pocketbase/pocketbase/core"
+)
+
+func TestRouteConflicts(t *testing.T) {
+  handler := func(e *core.RequestEvent) error {
+    return nil
+  }
+
+  tests := []struct {
+    name   string
+    routes func(groups RouterGroups) error
+    err    string
+  }{
+    {
+      name: "different methods",
+      routes: func(groups RouterGroups) error {
+        groups.Public.GET("/invoices", handler)
+        groups.Admin.POST("/invoices", handler)
+        return nil
+      },
+    },
+    {
+      name: "same method in different groups",
+      routes: func(groups RouterGroups) error {
+        groups.Public.GET("/invoices", handler)
+        groups.Admin.GET("/invoices", handler)
+        return nil
+      },
+      err: `route GET /api/invoices of module "billing" (group "admin") conflicts with route GET /api/invoices of module "billing" (group "public")`,
+    },
+    {
+      name: "path parameters with different names",
+      routes: func(groups RouterGroups) error {
+        groups.Public.GET("/invoices/{id}", handler)
+        groups.Public.GET("/invoices/{invoiceId}", handler)
+        return nil
+      },
+      err: "conflicts with",
+    },
+    {
+      name: "any method",
+      routes: func(groups RouterGroups) error {
+        groups.Public.GET("/invoices", handler)
+        groups.Public.Any("/invoices", handler)
+        return nil
+      },
+      err: "route ANY /api/invoices",
+    },
+  }
+
+  for _, test := range tests {
+    t.Run(
+      test.name, func(t *testing.T) {
+        m := NewModuleRegistry(nil, "/api")
+        m.Register(&testModule{name: "billing", routes: test.routes})
+
+        var err error
+        if m.roots, m.ordered, err = buildModuleTree(m.modules); err != nil {
+          t.Fatal(err)
+        }
+
+        _, err = m.Routes()
+        if test.err == "" && err != nil {
+          t.Fatalf("unexpected error: %v", err)
+        }
+        if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+          t.Fatalf("expected error containing %q, got %v", test.err, err)
+        }
+      },
+    )
+  }
+}
+
+func TestServeOrder(t *testing.T) {
+  served := []string{}
+  routes := func(module string) func(groups RouterGroups) error {
+    return func(groups RouterGroups) error {
+      served = append(served, module)
+      groups.Public.GET("/"+module, func(e *core.RequestEvent) error { return nil })
+      return nil
+    }
+  }
+
+  m := NewModuleRegistry(nil, "/api")
+  m.Register(
+    &testModule{
+      name:      "billing",
+      dependsOn: []string{"users"},
+      routes:    routes("billing"),
+      children:  []Module{&testModule{name: "invoices", dependsOn: []string{"crm"}, routes: routes("billing/invoices")}},
+    },
+  )
+  m.Register(&testModule{name: "users", routes: routes("users")})
+  m.Register(&testModule{name: "crm", routes: routes("crm")})
+
+  var err error
+  if m.roots, m.ordered, err = buildModuleTree(m.modules); err != nil {
+    t.Fatal(err)
+  }
+
+  if _, err := m.Routes(); err != nil {
+    t.Fatalf("unexpected error: %v", err)
+  }
+
+  expected := []string{"users", "billing", "crm", "billing/invoices"}
+  if strings.Join(served, ",") != strings.Join(expected, ",") {
+    t.Fatalf("expected the modules to be served in the order %v, got %v", expected, served)
+  }
+
+  roots := []string{}
+  for _, root := range m.roots {
+    roots = append(roots, root.path)
+  }
+  if strings.Join(roots, ",") != "users,billing,crm" {
+    t.Fatalf("expected the roots in dependency order, got %v", roots)
+  }
+}
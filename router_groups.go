@@ -1,20 +1,282 @@
 package pocketframework
 
 import (
+  "errors"
+  "fmt"
+  "net/http"
+  "reflect"
+  "runtime"
+  "strings"
+
+  "github.com/pocketbase/pocketbase/apis"
   "github.com/pocketbase/pocketbase/core"
+  "github.com/pocketbase/pocketbase/tools/hook"
   "github.com/pocketbase/pocketbase/tools/router"
 )
 
+const (
+  GroupPublic        = "public"
+  GroupAuthenticated = "authenticated"
+  GroupAdmin         = "admin"
+)
+
+// GroupDefinition defines a named router group which is available to every module, in addition to the built-in
+// public, authenticated and admin groups. For example:
+//
+//  GroupDefinition{Name: "customers", Middlewares: []*hook.Handler[*core.RequestEvent]{apis.RequireAuth("customers")}, Security: SecuritySchemeAuth}
+//  GroupDefinition{Name: "guests", Middlewares: []*hook.Handler[*core.RequestEvent]{apis.RequireGuestOnly()}}
+type GroupDefinition struct {
+  Name string
+
+  // Middlewares are bound to the group before the middlewares of any module.
+  Middlewares []*hook.Handler[*core.RequestEvent]
+
+  // Security is the OpenAPI security scheme required by the routes of the group, e.g. SecuritySchemeAuth.
+  Security string
+}
+
+// WithRouterGroup adds a named router group to the registry. Modules access it through RouterGroups.Group.
+func WithRouterGroup(group GroupDefinition) RegistryOption {
+  return func(m *ModuleRegistry) {
+    m.groups = append(m.groups, group)
+  }
+}
+
+// groupDefinitions returns the built-in groups followed by the groups added with WithRouterGroup.
+func (m *ModuleRegistry) groupDefinitions() []GroupDefinition {
+  return append(
+    []GroupDefinition{
+      {Name: GroupPublic},
+      {Name: GroupAuthenticated, Middlewares: []*hook.Handler[*core.RequestEvent]{apis.RequireAuth()}, Security: SecuritySchemeAuth},
+      {Name: GroupAdmin, Middlewares: []*hook.Handler[*core.RequestEvent]{apis.RequireSuperuserAuth()}, Security: SecuritySchemeSuperuser},
+    },
+    m.groups...,
+  )
+}
+
+func (m *ModuleRegistry) validateGroupDefinitions() error {
+  names := map[string]bool{}
+  for _, group := range m.groupDefinitions() {
+    if group.Name == "" {
+      return errors.New("router group has no name")
+    }
+    if names[group.Name] {
+      return fmt.Errorf("router group %q is defined more than once", group.Name)
+    }
+    names[group.Name] = true
+  }
+
+  return nil
+}
+
 type RouterGroups struct {
-  Public        *router.RouterGroup[*core.RequestEvent]
-  Authenticated *router.RouterGroup[*core.RequestEvent]
-  Admin         *router.RouterGroup[*core.RequestEvent]
+  Public        *RouterGroup
+  Authenticated *RouterGroup
+  Admin         *RouterGroup
+
+  groups []*RouterGroup
+}
+
+func newRouterGroups(groups []*RouterGroup) RouterGroups {
+  result := RouterGroups{groups: groups}
+  for _, group := range groups {
+    switch group.name {
+    case GroupPublic:
+      result.Public = group
+    case GroupAuthenticated:
+      result.Authenticated = group
+    case GroupAdmin:
+      result.Admin = group
+    }
+  }
+
+  return result
+}
+
+// Group returns the router group with the given name, including the built-in groups.
+func (r RouterGroups) Group(name string) (*RouterGroup, error) {
+  for _, group := range r.groups {
+    if group.name == name {
+      return group, nil
+    }
+  }
+
+  return nil, fmt.Errorf("unknown router group %q", name)
+}
+
+// Names returns the names of all groups, the built-in groups first.
+func (r RouterGroups) Names() []string {
+  names := make([]string, 0, len(r.groups))
+  for _, group := range r.groups {
+    names = append(names, group.name)
+  }
+
+  return names
 }
 
 func (r RouterGroups) WithPrefix(prefix string) RouterGroups {
-  return RouterGroups{
-    Public:        r.Public.Group(prefix),
-    Authenticated: r.Authenticated.Group(prefix),
-    Admin:         r.Admin.Group(prefix),
+  groups := make([]*RouterGroup, 0, len(r.groups))
+  for _, group := range r.groups {
+    groups = append(groups, group.Group(prefix))
+  }
+
+  return newRouterGroups(groups)
+}
+
+// Bind binds the middlewares to all groups.
+func (r RouterGroups) Bind(middlewares ...*hook.Handler[*core.RequestEvent]) {
+  for _, group := range r.groups {
+    group.Bind(middlewares...)
+  }
+}
+
+// forModule returns sub groups for the given module, attributing all routes registered through them to it.
+func (r RouterGroups) forModule(module string, prefix string) RouterGroups {
+  groups := r.WithPrefix(prefix)
+  for _, group := range groups.groups {
+    group.module = module
+  }
+
+  return groups
+}
+
+// RouterGroup is a pocketbase router group which records every route registered through it.
+type RouterGroup struct {
+  *router.RouterGroup[*core.RequestEvent]
+
+  name   string
+  prefix string
+  module string
+  table  *routeTable
+}
+
+func newRouterGroup(group *router.RouterGroup[*core.RequestEvent], name string, prefix string, table *routeTable) *RouterGroup {
+  return &RouterGroup{
+    RouterGroup: group,
+    name:        name,
+    prefix:      prefix,
+    table:       table,
+  }
+}
+
+// Name returns the name of the group this router group belongs to, e.g. GroupPublic.
+func (g *RouterGroup) Name() string {
+  return g.name
+}
+
+// Group creates and registers a new child group with the given prefix.
+func (g *RouterGroup) Group(prefix string) *RouterGroup {
+  return &RouterGroup{
+    RouterGroup: g.RouterGroup.Group(prefix),
+    name:        g.name,
+    prefix:      g.prefix + prefix,
+    module:      g.module,
+    table:       g.table,
+  }
+}
+
+// Route registers a single route into the current group.
+func (g *RouterGroup) Route(method string, path string, action func(e *core.RequestEvent) error) *Route {
+  route := &Route{
+    Route: g.RouterGroup.Route(method, path, action),
+    info: RouteInfo{
+      Method:  method,
+      Path:    g.prefix + path,
+      Group:   g.name,
+      Module:  g.module,
+      Handler: funcName(action),
+    },
+  }
+  if route.info.Method == "" {
+    route.info.Method = MethodAny
+  }
+
+  if g.table.metrics != nil {
+    route.Bind(g.table.metrics.routeMetricsMiddleware(route.info))
+  }
+  if g.table.tracer != nil && g.table.tracer.Enabled() {
+    route.Bind(g.table.tracer.routeTracingMiddleware(route.info))
+  }
+
+  g.table.add(route)
+
+  return route
+}
+
+// Any is a shorthand for [RouterGroup.Route] with "" as route method (aka. matches any method).
+func (g *RouterGroup) Any(path string, action func(e *core.RequestEvent) error) *Route {
+  return g.Route("", path, action)
+}
+
+// GET is a shorthand for [RouterGroup.Route] with GET as route method.
+func (g *RouterGroup) GET(path string, action func(e *core.RequestEvent) error) *Route {
+  return g.Route(http.MethodGet, path, action)
+}
+
+// SEARCH is a shorthand for [RouterGroup.Route] with SEARCH as route method.
+func (g *RouterGroup) SEARCH(path string, action func(e *core.RequestEvent) error) *Route {
+  return g.Route("SEARCH", path, action)
+}
+
+// POST is a shorthand for [RouterGroup.Route] with POST as route method.
+func (g *RouterGroup) POST(path string, action func(e *core.RequestEvent) error) *Route {
+  return g.Route(http.MethodPost, path, action)
+}
+
+// DELETE is a shorthand for [RouterGroup.Route] with DELETE as route method.
+func (g *RouterGroup) DELETE(path string, action func(e *core.RequestEvent) error) *Route {
+  return g.Route(http.MethodDelete, path, action)
+}
+
+// PATCH is a shorthand for [RouterGroup.Route] with PATCH as route method.
+func (g *RouterGroup) PATCH(path string, action func(e *core.RequestEvent) error) *Route {
+  return g.Route(http.MethodPatch, path, action)
+}
+
+// PUT is a shorthand for [RouterGroup.Route] with PUT as route method.
+func (g *RouterGroup) PUT(path string, action func(e *core.RequestEvent) error) *Route {
+  return g.Route(http.MethodPut, path, action)
+}
+
+// HEAD is a shorthand for [RouterGroup.Route] with HEAD as route method.
+func (g *RouterGroup) HEAD(path string, action func(e *core.RequestEvent) error) *Route {
+  return g.Route(http.MethodHead, path, action)
+}
+
+// OPTIONS is a shorthand for [RouterGroup.Route] with OPTIONS as route method.
+func (g *RouterGroup) OPTIONS(path string, action func(e *core.RequestEvent) error) *Route {
+  return g.Route(http.MethodOptions, path, action)
+}
+
+// Route is a pocketbase route registered through a RouterGroup.
+type Route struct {
+  *router.Route[*core.RequestEvent]
+
+  info RouteInfo
+}
+
+// Info returns the recorded information of this route.
+func (r *Route) Info() RouteInfo {
+  return r.info
+}
+
+// Doc attaches documentation to this route which is used when generating the OpenAPI document.
+func (r *Route) Doc(doc RouteDoc) *Route {
+  r.info.Doc = &doc
+  return r
+}
+
+// funcName returns the package qualified name of a function, e.g. "invoices.(*Module).list-fm".
+func funcName(fn any) string {
+  value := reflect.ValueOf(fn)
+  if value.Kind() != reflect.Func || value.IsNil() {
+    return ""
   }
+
+  name := runtime.FuncForPC(value.Pointer()).Name()
+  if i := strings.LastIndex(name, "/"); i >= 0 {
+    name = name[i+1:]
+  }
+
+  return name
 }
@@ -0,0 +1,155 @@
+package pocketframework
+
+import (
+  "net/http"
+  "net/http/httptest"
+  "strings"
+  "testing"
+
+  "github.com/pocketbase/pocketbase/core"
+  "github.com/pocketbase/pocketbase/tools/hook"
+)
+
+func TestNamedRouterGroups(t *testing.T) {
+  partners := GroupDefinition{
+    Name: "partners",
+    Middlewares: []*hook.Handler[*core.RequestEvent]{
+      {
+        Func: func(e *core.RequestEvent) error {
+          if e.Request.Header.Get("X-Partner-Key") != "secret" {
+            return e.ForbiddenError("", nil)
+          }
+          return e.Next()
+        },
+      },
+    },
+    Security: SecuritySchemeAuth,
+  }
+
+  var names []string
+  var groupErr error
+  registry, app := newTestRegistryWithOptions(
+    t, []RegistryOption{WithRouterGroup(partners)}, &testModule{
+      name:   "billing",
+      prefix: "/billing",
+      routes: func(groups RouterGroups) error {
+        names = groups.Names()
+        _, groupErr = groups.Group("unknown")
+
+        group, err := groups.Group("partners")
+        if err != nil {
+          return err
+        }
+        group.GET(
+          "/invoices", func(e *core.RequestEvent) error {
+            return e.NoContent(http.StatusNoContent)
+          },
+        )
+        return nil
+      },
+    },
+  )
+  handler := serveTestRegistry(t, registry, app)
+
+  if strings.Join(names, ",") != "public,authenticated,admin,partners" {
+    t.Errorf("expected the built-in groups followed by the named groups, got %v", names)
+  }
+  if groupErr == nil || groupErr.Error() != `unknown router group "unknown"` {
+    t.Errorf("expected an error for an unknown group, got %v", groupErr)
+  }
+
+  routes, err := registry.Routes()
+  if err != nil {
+    t.Fatal(err)
+  }
+  var route *RouteInfo
+  for i := range routes {
+    if routes[i].Path == "/api/billing/invoices" {
+      route = &routes[i]
+    }
+  }
+  if route == nil || route.Group != "partners" || route.Module != "billing" {
+    t.Fatalf("expected the route to be recorded in the group %q, got %+v", "partners", route)
+  }
+
+  doc, err := registry.OpenAPI()
+  if err != nil {
+    t.Fatal(err)
+  }
+  if security := doc.Paths["/api/billing/invoices"]["get"].Security; len(security) != 1 || security[0][SecuritySchemeAuth] == nil {
+    t.Errorf("expected the security scheme of the group, got %v", security)
+  }
+
+  tests := []struct {
+    name   string
+    key    string
+    status int
+  }{
+    {name: "rejected by the group middleware", status: http.StatusForbidden},
+    {name: "accepted by the group middleware", key: "secret", status: http.StatusNoContent},
+  }
+
+  for _, test := range tests {
+    t.Run(
+      test.name, func(t *testing.T) {
+        request := httptest.NewRequest(http.MethodGet, "/api/billing/invoices", nil)
+        if test.key != "" {
+          request.Header.Set("X-Partner-Key", test.key)
+        }
+        recorder := httptest.NewRecorder()
+        handler.ServeHTTP(recorder, request)
+
+        if recorder.Code != test.status {
+          t.Fatalf("expected status %d, got %d: %s", test.status, recorder.Code, recorder.Body)
+        }
+      },
+    )
+  }
+}
+
+func TestValidateGroupDefinitions(t *testing.T) {
+  tests := []struct {
+    name   string
+    groups []GroupDefinition
+    err    string
+  }{
+    {
+      name:   "distinct names",
+      groups: []GroupDefinition{{Name: "partners"}, {Name: "customers"}},
+    },
+    {
+      name:   "missing name",
+      groups: []GroupDefinition{{}},
+      err:    "router group has no name",
+    },
+    {
+      name:   "duplicate name",
+      groups: []GroupDefinition{{Name: "partners"}, {Name: "partners"}},
+      err:    `router group "partners" is defined more than once`,
+    },
+    {
+      name:   "built-in name",
+      groups: []GroupDefinition{{Name: GroupAdmin}},
+      err:    `router group "admin" is defined more than once`,
+    },
+  }
+
+  for _, test := range tests {
+    t.Run(
+      test.name, func(t *testing.T) {
+        opts := []RegistryOption{}
+        for _, group := range test.groups {
+          opts = append(opts, WithRouterGroup(group))
+        }
+
+        err := NewModuleRegistry(nil, "/api", opts...).validateGroupDefinitions()
+        if test.err == "" && err != nil {
+          t.Fatalf("unexpected error: %v", err)
+        }
+        if test.err != "" && (err == nil || err.Error() != test.err) {
+          t.Fatalf("expected the error %q, got %v", test.err, err)
+        }
+      },
+    )
+  }
+}
@@ -0,0 +1,194 @@
+package pocketframework
+
+import (
+  "errors"
+  "fmt"
+  "reflect"
+  "sort"
+  "strings"
+  "sync"
+)
+
+// frameworkProvider is recorded as provider of the services of the framework itself.
+const frameworkProvider = "pocketframework"
+
+// ServiceContainer holds typed services which modules share with each other.
+type ServiceContainer struct {
+  store    *serviceStore
+  provider string
+}
+
+type serviceStore struct {
+  mu        sync.RWMutex
+  services  map[reflect.Type]any
+  providers map[reflect.Type]string
+}
+
+// ServiceResolver gives read-only access to the services of a container. It is passed to every ServiceConsumer.
+type ServiceResolver interface {
+  resolve(typ reflect.Type) (service any, provider string, ok bool)
+}
+
+// ServiceKey identifies a service by its type. Use ServiceOf to create one.
+type ServiceKey struct {
+  typ reflect.Type
+}
+
+func (k ServiceKey) String() string {
+  return k.typ.String()
+}
+
+// ServiceOf returns the key of the service of type T.
+func ServiceOf[T any]() ServiceKey {
+  return ServiceKey{typ: reflect.TypeFor[T]()}
+}
+
+func NewServiceContainer() *ServiceContainer {
+  return &ServiceContainer{
+    store: &serviceStore{
+      services:  map[reflect.Type]any{},
+      providers: map[reflect.Type]string{},
+    },
+  }
+}
+
+// Provide registers service as the implementation of T. Every type can only be provided once.
+func Provide[T any](c *ServiceContainer, service T) error {
+  return c.provide(reflect.TypeFor[T](), service)
+}
+
+// Resolve returns the service of type T.
+func Resolve[T any](r ServiceResolver) (T, error) {
+  var zero T
+
+  service, provider, ok := r.resolve(reflect.TypeFor[T]())
+  if !ok {
+    return zero, fmt.Errorf("no service of type %s has been provided", reflect.TypeFor[T]())
+  }
+
+  typed, ok := service.(T)
+  if !ok {
+    return zero, fmt.Errorf("service %s provided by %q has the unexpected type %T", reflect.TypeFor[T](), provider, service)
+  }
+
+  return typed, nil
+}
+
+// MustResolve is like Resolve but panics if the service has not been provided.
+func MustResolve[T any](r ServiceResolver) T {
+  service, err := Resolve[T](r)
+  if err != nil {
+    panic(err)
+  }
+
+  return service
+}
+
+// Has reports whether a service has been provided for the given key.
+func (c *ServiceContainer) Has(key ServiceKey) bool {
+  c.store.mu.RLock()
+  defer c.store.mu.RUnlock()
+
+  _, ok := c.store.services[key.typ]
+  return ok
+}
+
+func (c *ServiceContainer) resolve(typ reflect.Type) (any, string, bool) {
+  c.store.mu.RLock()
+  defer c.store.mu.RUnlock()
+
+  service, ok := c.store.services[typ]
+  return service, c.store.providers[typ], ok
+}
+
+// withProvider returns a view of the container which records the given module as provider.
+func (c *ServiceContainer) withProvider(provider string) *ServiceContainer {
+  return &ServiceContainer{store: c.store, provider: provider}
+}
+
+func (c *ServiceContainer) provide(typ reflect.Type, service any) error {
+  if service == nil {
+    return fmt.Errorf("service %s provided by %q is nil", typ, c.provider)
+  }
+
+  c.store.mu.Lock()
+  defer c.store.mu.Unlock()
+
+  if existing, ok := c.store.providers[typ]; ok {
+    return fmt.Errorf("service %s has already been provided by %q", typ, existing)
+  }
+
+  c.store.services[typ] = service
+  c.store.providers[typ] = c.provider
+
+  return nil
+}
+
+// Services returns the service container shared by all registered modules.
+func (m *ModuleRegistry) Services() *ServiceContainer {
+  return m.services
+}
+
+// provideServices lets all modules provide their services, verifies that every required service is available and
+// hands the services to their consumers.
+func (m *ModuleRegistry) provideServices() error {
+  framework := m.services.withProvider(frameworkProvider)
+  if err := errors.Join(Provide(framework, m.queue), Provide(framework, m.events), Provide(framework, m.tracer)); err != nil {
+    return err
+  }
+
+  err := m.forEachModule(
+    func(node *moduleNode) error {
+      provider, ok := node.module.(ServiceProvider)
+      if !ok {
+        return nil
+      }
+
+      if err := provider.ProvideServices(m.services.withProvider(node.path)); err != nil {
+        return fmt.Errorf("module %q failed to provide its services: %w", node.path, err)
+      }
+      return nil
+    },
+  )
+  if err != nil {
+    return err
+  }
+
+  missing := []string{}
+  for _, root := range m.roots {
+    _ = root.walk(
+      func(node *moduleNode) error {
+        consumer, ok := node.module.(ServiceConsumer)
+        if !ok {
+          return nil
+        }
+
+        for _, key := range consumer.RequiredServices() {
+          if !m.services.Has(key) {
+            missing = append(missing, fmt.Sprintf("%s (required by %q)", key, node.path))
+          }
+        }
+        return nil
+      },
+    )
+  }
+
+  if len(missing) > 0 {
+    sort.Strings(missing)
+    return fmt.Errorf("missing service providers: %s", strings.Join(missing, ", "))
+  }
+
+  return m.forEachModule(
+    func(node *moduleNode) error {
+      consumer, ok := node.module.(ServiceConsumer)
+      if !ok {
+        return nil
+      }
+
+      if err := consumer.ResolveServices(m.services); err != nil {
+        return fmt.Errorf("module %q failed to resolve its services: %w", node.path, err)
+      }
+      return nil
+    },
+  )
+}
@@ -0,0 +1,133 @@
+package pocketframework
+
+import (
+  "strings"
+  "testing"
+)
+
+type testGreeter interface {
+  Greet() string
+}
+
+type testEnglishGreeter struct{}
+
+func (testEnglishGreeter) Greet() string {
+  return "hello"
+}
+
+type testConsumer struct {
+  testModule
+
+  greeter testGreeter
+}
+
+func (c *testConsumer) RequiredServices() []ServiceKey {
+  return []ServiceKey{ServiceOf[testGreeter]()}
+}
+
+func (c *testConsumer) ResolveServices(services ServiceResolver) error {
+  greeter, err := Resolve[testGreeter](services)
+  c.greeter = greeter
+  return err
+}
+
+type testProvider struct {
+  testModule
+
+  provide func(services *ServiceContainer) error
+}
+
+func (p *testProvider) ProvideServices(services *ServiceContainer) error {
+  return p.provide(services)
+}
+
+func TestServiceContainer(t *testing.T) {
+  tests := []struct {
+    name    string
+    provide func(c *ServiceContainer) error
+    err     string
+  }{
+    {
+      name: "provided",
+      provide: func(c *ServiceContainer) error {
+        return Provide[testGreeter](c, testEnglishGreeter{})
+      },
+    },
+    {
+      name:    "missing",
+      provide: func(c *ServiceContainer) error { return nil },
+      err:     "no service of type pocketframework.testGreeter has been provided",
+    },
+    {
+      name: "nil interface",
+      provide: func(c *ServiceContainer) error {
+        return Provide[testGreeter](c, nil)
+      },
+      err: "is nil",
+    },
+    {
+      name: "provided twice",
+      provide: func(c *ServiceContainer) error {
+        _ = Provide[testGreeter](c.withProvider("a"), testEnglishGreeter{})
+        return Provide[testGreeter](c.withProvider("b"), testEnglishGreeter{})
+      },
+      err: `has already been provided by "a"`,
+    },
+    {
+      name: "unexpected type",
+      provide: func(c *ServiceContainer) error {
+        return c.withProvider("a").provide(ServiceOf[testGreeter]().typ, "not a greeter")
+      },
+      err: `provided by "a" has the unexpected type string`,
+    },
+  }
+
+  for _, test := range tests {
+    t.Run(
+      test.name, func(t *testing.T) {
+        c := NewServiceContainer()
+
+        err := test.provide(c)
+        if err == nil {
+          var greeter testGreeter
+          if greeter, err = Resolve[testGreeter](c); err == nil && greeter.Greet() != "hello" {
+            t.Fatalf("unexpected greeting %q", greeter.Greet())
+          }
+        }
+
+        if test.err == "" && err != nil {
+          t.Fatalf("unexpected error: %v", err)
+        }
+        if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+          t.Fatalf("expected error containing %q, got %v", test.err, err)
+        }
+      },
+    )
+  }
+}
+
+func TestProvideServicesResolvesConsumers(t *testing.T) {
+  consumer := &testConsumer{testModule: testModule{name: "consumer", dependsOn: []string{"provider"}}}
+  provider := &testProvider{
+    testModule: testModule{name: "provider"},
+    provide: func(services *ServiceContainer) error {
+      return Provide[testGreeter](services, testEnglishGreeter{})
+    },
+  }
+
+  m := NewModuleRegistry(nil, "/api")
+  m.Register(consumer)
+  m.Register(provider)
+
+  var err error
+  if m.roots, m.ordered, err = buildModuleTree(m.modules); err != nil {
+    t.Fatal(err)
+  }
+  if err := m.provideServices(); err != nil {
+    t.Fatal(err)
+  }
+
+  if consumer.greeter == nil || consumer.greeter.Greet() != "hello" {
+    t.Fatalf("the consumer did not receive its service")
+  }
+}
@@ -0,0 +1,403 @@
+package pocketframework
+
+import (
+  "context"
+  "crypto/rand"
+  "database/sql"
+  "encoding/hex"
+  "encoding/json"
+  "fmt"
+  "net/http"
+  "os"
+  "reflect"
+  "regexp"
+  "strconv"
+  "strings"
+  "sync"
+  "time"
+
+  "github.com/pocketbase/dbx"
+  "github.com/pocketbase/pocketbase/core"
+  "github.com/pocketbase/pocketbase/tools/hook"
+  "github.com/pocketbase/pocketbase/tools/router"
+)
+
+// TraceparentHeader is the W3C trace context header used to propagate traces between services.
+const TraceparentHeader = "traceparent"
+
+// tracingPriority makes the request span the first and last middleware of every route.
+const tracingPriority = metricsPriority - 1
+
+var traceparentRegex = regexp.MustCompile(`^00-([0-9a-f]{32})-([0-9a-f]{16})-[0-9a-f]{2}$`)
+
+// Span is a single timed operation of a trace, e.g. a request, a hook handler or a DB query.
+type Span struct {
+  TraceId    string            `json:"traceId"`
+  SpanId     string            `json:"spanId"`
+  ParentId   string            `json:"parentId,omitempty"`
+  Name       string            `json:"name"`
+  Start      time.Time         `json:"start"`
+  End        time.Time         `json:"end"`
+  Attributes map[string]string `json:"attributes,omitempty"`
+  Error      string            `json:"error,omitempty"`
+
+  tracer *Tracer
+}
+
+// SpanExporter receives every finished span.
+type SpanExporter interface {
+  ExportSpan(span Span) error
+}
+
+// Tracer creates spans and passes them to the exporter once they are finished. Without an exporter it does nothing,
+// so modules can always create spans. The registry provides it as a service, use Resolve[*Tracer] to access it.
+type Tracer struct {
+  app        core.App
+  exporter   SpanExporter
+  statements bool
+}
+
+type spanKey struct{}
+
+// WithTracing enables tracing of requests, hooks and DB queries and exports the spans to the given exporter, e.g. a
+// FileExporter.
+func WithTracing(exporter SpanExporter) RegistryOption {
+  return func(m *ModuleRegistry) {
+    m.tracer.exporter = exporter
+  }
+}
+
+// WithDBStatements records the SQL of the traced DB queries in the db.statement attribute of their spans. The SQL
+// contains the values of the bound params, e.g. emails or password hashes, so only enable it if the exported spans
+// may hold them. By default the spans only record the operation of the query in db.operation.
+func WithDBStatements() RegistryOption {
+  return func(m *ModuleRegistry) {
+    m.tracer.statements = true
+  }
+}
+
+// Tracer returns the tracer of the registry.
+func (m *ModuleRegistry) Tracer() *Tracer {
+  return m.tracer
+}
+
+// Enabled reports whether spans are exported.
+func (t *Tracer) Enabled() bool {
+  return t.exporter != nil
+}
+
+// Start starts a span as child of the span in ctx and returns a ctx containing the new span. If tracing is disabled,
+// ctx is returned as is together with a nil span, which is safe to use.
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, *Span) {
+  if !t.Enabled() {
+    return ctx, nil
+  }
+
+  span := t.newSpan(SpanFromContext(ctx), name)
+  return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// SpanFromContext returns the current span of ctx or nil if there is none.
+func SpanFromContext(ctx context.Context) *Span {
+  span, _ := ctx.Value(spanKey{}).(*Span)
+  return span
+}
+
+// InjectTraceparent adds the traceparent header of the current span of ctx to an outgoing request.
+func InjectTraceparent(ctx context.Context, header http.Header) {
+  if span := SpanFromContext(ctx); span != nil {
+    header.Set(TraceparentHeader, span.Traceparent())
+  }
+}
+
+// SetAttribute sets an attribute of the span.
+func (s *Span) SetAttribute(key string, value string) {
+  if s == nil {
+    return
+  }
+
+  if s.Attributes == nil {
+    s.Attributes = map[string]string{}
+  }
+  s.Attributes[key] = value
+}
+
+// Traceparent returns the W3C traceparent header value of the span.
+func (s *Span) Traceparent() string {
+  if s == nil {
+    return ""
+  }
+
+  return "00-" + s.TraceId + "-" + s.SpanId + "-01"
+}
+
+// Finish ends the span, records err if it is not nil and exports it.
+func (s *Span) Finish(err error) {
+  if s == nil {
+    return
+  }
+
+  s.End = time.Now()
+  if err != nil {
+    s.Error = err.Error()
+  }
+
+  s.tracer.export(s)
+}
+
+func (t *Tracer) newSpan(parent *Span, name string) *Span {
+  span := &Span{
+    SpanId: randomHex(8),
+    Name:   name,
+    Start:  time.Now(),
+    tracer: t,
+  }
+
+  if parent != nil {
+    span.TraceId = parent.TraceId
+    span.ParentId = parent.SpanId
+  } else {
+    span.TraceId = randomHex(16)
+  }
+
+  return span
+}
+
+func (t *Tracer) export(span *Span) {
+  if err := t.exporter.ExportSpan(*span); err != nil {
+    t.app.Logger().Warn("Failed to export a span", "span", span.Name, "error", err)
+  }
+}
+
+// requestTracingMiddleware starts a span for every request handled by the router, including the routes of pocketbase
+// itself. The trace is continued if the request has a valid traceparent header and the traceparent of the request
+// span is sent back.
+func (t *Tracer) requestTracingMiddleware() *hook.Handler[*core.RequestEvent] {
+  return &hook.Handler[*core.RequestEvent]{
+    Priority: tracingPriority,
+    Func: func(e *core.RequestEvent) error {
+      var parent *Span
+      if match := traceparentRegex.FindStringSubmatch(e.Request.Header.Get(TraceparentHeader)); match != nil {
+        parent = &Span{TraceId: match[1], SpanId: match[2]}
+      }
+
+      route := e.Request.URL.Path
+      if _, pattern, ok := strings.Cut(e.Request.Pattern, " "); ok {
+        route = pattern
+      } else if e.Request.Pattern != "" {
+        route = e.Request.Pattern
+      }
+
+      span := t.newSpan(parent, e.Request.Method+" "+route)
+      span.SetAttribute("http.method", e.Request.Method)
+      span.SetAttribute("http.route", route)
+
+      e.Request = e.Request.WithContext(context.WithValue(e.Request.Context(), spanKey{}, span))
+      e.Response.Header().Set(TraceparentHeader, span.Traceparent())
+
+      err := e.Next()
+
+      status := e.Status()
+      if err != nil {
+        status = router.ToApiError(err).Status
+      }
+      span.SetAttribute("http.status_code", strconv.Itoa(status))
+      span.Finish(err)
+
+      return err
+    },
+  }
+}
+
+// routeTracingMiddleware adds the module and router group of a module route to the request span.
+func (t *Tracer) routeTracingMiddleware(info RouteInfo) *hook.Handler[*core.RequestEvent] {
+  return &hook.Handler[*core.RequestEvent]{
+    Priority: tracingPriority,
+    Func: func(e *core.RequestEvent) error {
+      span := SpanFromContext(e.Request.Context())
+      span.SetAttribute("module", info.Module)
+      span.SetAttribute("group", info.Group)
+
+      return e.Next()
+    },
+  }
+}
+
+// startHookSpan starts a span for a hook handler invocation as child of the span of the event. Events without a
+// Context field or request start a new trace.
+//
+// The span is put into the context of the event until restore is called, so the DB queries and nested hooks run by
+// the handler become its children.
+func (t *Tracer) startHookSpan(module string, hookName string, event any) (span *Span, restore func()) {
+  if !t.Enabled() {
+    return nil, func() {}
+  }
+
+  ctx := eventContext(event)
+  span = t.newSpan(SpanFromContext(ctx), hookName)
+  span.SetAttribute("module", module)
+
+  return span, setEventContext(event, context.WithValue(ctx, spanKey{}, span))
+}
+
+// traceDB records a span for every query of the given DB which is executed with a ctx containing a span, e.g.
+// txApp.DB().NewQuery(...).WithContext(e.Request.Context()).
+func (t *Tracer) traceDB(builder dbx.Builder) {
+  db, ok := builder.(*dbx.DB)
+  if !ok || !t.Enabled() {
+    return
+  }
+
+  record := func(ctx context.Context, duration time.Duration, query string, err error) {
+    if ctx == nil {
+      return
+    }
+
+    parent := SpanFromContext(ctx)
+    if parent == nil {
+      return
+    }
+
+    span := t.newSpan(parent, "db.query")
+    span.Start = time.Now().Add(-duration)
+    span.SetAttribute("db.operation", queryOperation(query))
+    if t.statements {
+      span.SetAttribute("db.statement", query)
+    }
+    span.Finish(err)
+  }
+
+  queryLogFunc := db.QueryLogFunc
+  db.QueryLogFunc = func(ctx context.Context, duration time.Duration, query string, rows *sql.Rows, err error) {
+    record(ctx, duration, query, err)
+    if queryLogFunc != nil {
+      queryLogFunc(ctx, duration, query, rows, err)
+    }
+  }
+
+  execLogFunc := db.ExecLogFunc
+  db.ExecLogFunc = func(ctx context.Context, duration time.Duration, query string, result sql.Result, err error) {
+    record(ctx, duration, query, err)
+    if execLogFunc != nil {
+      execLogFunc(ctx, duration, query, result, err)
+    }
+  }
+}
+
+// queryOperation returns the first keyword of a SQL query, e.g. SELECT.
+func queryOperation(query string) string {
+  operation, _, _ := strings.Cut(strings.TrimSpace(query), " ")
+  return strings.ToUpper(operation)
+}
+
+// close closes the exporter if it holds resources, e.g. the file of a FileExporter.
+func (t *Tracer) close() error {
+  if closer, ok := t.exporter.(interface{ Close() error }); ok {
+    return closer.Close()
+  }
+
+  return nil
+}
+
+// eventContext returns the context of a hook event, taken from its Context field or its request.
+func eventContext(event any) context.Context {
+  value := reflect.ValueOf(event)
+  for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+    if value.IsNil() {
+      return context.Background()
+    }
+    value = value.Elem()
+  }
+  if value.Kind() != reflect.Struct {
+    return context.Background()
+  }
+
+  if field := value.FieldByName("Context"); field.IsValid() && field.CanInterface() {
+    if ctx, ok := field.Interface().(context.Context); ok && ctx != nil {
+      return ctx
+    }
+  }
+
+  if field := value.FieldByName("Request"); field.IsValid() && field.CanInterface() {
+    if request, ok := field.Interface().(*http.Request); ok && request != nil {
+      return request.Context()
+    }
+  }
+
+  return context.Background()
+}
+
+// setEventContext replaces the context of a hook event, i.e. its Context field or the context of its request, and
+// returns a function which restores the previous one. Events without either are left as is.
+func setEventContext(event any, ctx context.Context) (restore func()) {
+  value := reflect.ValueOf(event)
+  for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+    if value.IsNil() {
+      return func() {}
+    }
+    value = value.Elem()
+  }
+  if value.Kind() != reflect.Struct {
+    return func() {}
+  }
+
+  if field := value.FieldByName("Context"); field.IsValid() && field.CanSet() && field.Type() == reflect.TypeFor[context.Context]() {
+    previous := reflect.New(field.Type()).Elem()
+    previous.Set(field)
+    field.Set(reflect.ValueOf(&ctx).Elem())
+    return func() {
+      field.Set(previous)
+    }
+  }
+
+  if field := value.FieldByName("Request"); field.IsValid() && field.CanSet() && field.Type() == reflect.TypeFor[*http.Request]() {
+    if request, ok := field.Interface().(*http.Request); ok && request != nil {
+      field.Set(reflect.ValueOf(request.WithContext(ctx)))
+      return func() {
+        field.Set(reflect.ValueOf(request))
+      }
+    }
+  }
+
+  return func() {}
+}
+
+func randomHex(bytes int) string {
+  data := make([]byte, bytes)
+  _, _ = rand.Read(data)
+
+  return hex.EncodeToString(data)
+}
+
+// FileExporter writes every span as a JSON line to a file, e.g. for inspecting traces offline.
+type FileExporter struct {
+  mu      sync.Mutex
+  file    *os.File
+  encoder *json.Encoder
+}
+
+// NewFileExporter opens the given file for appending, creating it if necessary.
+func NewFileExporter(path string) (*FileExporter, error) {
+  file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+  if err != nil {
+    return nil, fmt.Errorf("failed to open the trace file %q: %w", path, err)
+  }
+
+  return &FileExporter{file: file, encoder: json.NewEncoder(file)}, nil
+}
+
+// ExportSpan implements SpanExporter.
+func (e *FileExporter) ExportSpan(span Span) error {
+  e.mu.Lock()
+  defer e.mu.Unlock()
+
+  return e.encoder.Encode(span)
+}
+
+// Close closes the file.
+func (e *FileExporter) Close() error {
+  e.mu.Lock()
+  defer e.mu.Unlock()
+
+  return e.file.Close()
+}
@@ -0,0 +1,232 @@
+package pocketframework
+
+import (
+  "net/http"
+  "net/http/httptest"
+  "strings"
+  "sync"
+  "testing"
+
+  "github.com/pocketbase/pocketbase/core"
+)
+
+// memoryExporter keeps the exported spans in memory.
+type memoryExporter struct {
+  mu    sync.Mutex
+  spans []Span
+}
+
+func (e *memoryExporter) ExportSpan(span Span) error {
+  e.mu.Lock()
+  defer e.mu.Unlock()
+
+  e.spans = append(e.spans, span)
+  return nil
+}
+
+// find returns the exported spans with the given name.
+func (e *memoryExporter) find(name string) []Span {
+  e.mu.Lock()
+  defer e.mu.Unlock()
+
+  spans := []Span{}
+  for _, span := range e.spans {
+    if span.Name == name {
+      spans = append(spans, span)
+    }
+  }
+
+  return spans
+}
+
+// childrenOf returns the exported spans whose parent is the given span.
+func (e *memoryExporter) childrenOf(parent Span) []Span {
+  e.mu.Lock()
+  defer e.mu.Unlock()
+
+  spans := []Span{}
+  for _, span := range e.spans {
+    if span.ParentId == parent.SpanId && span.TraceId == parent.TraceId {
+      spans = append(spans, span)
+    }
+  }
+
+  return spans
+}
+
+// hooksModule is a test module which binds a handler to OnRecordCreate.
+type hooksModule struct {
+  testModule
+}
+
+func (h *hooksModule) RegisterHooks(app ModuleAppHooks) error {
+  app.OnRecordCreate().BindFunc(
+    func(e *core.RecordEvent) error {
+      return e.Next()
+    },
+  )
+  return nil
+}
+
+func TestHookSpanContext(t *testing.T) {
+  exporter := &memoryExporter{}
+  _, app := newTestRegistryWithOptions(t, []RegistryOption{WithTracing(exporter)}, &hooksModule{testModule{name: "notes"}})
+
+  collection := core.NewBaseCollection("notes")
+  collection.Fields.Add(&core.TextField{Name: "title"})
+  if err := app.Save(collection); err != nil {
+    t.Fatalf("failed to create the collection: %v", err)
+  }
+
+  record := core.NewRecord(collection)
+  record.Set("title", "first")
+  if err := app.Save(record); err != nil {
+    t.Fatalf("failed to create the record: %v", err)
+  }
+
+  spans := exporter.find("OnRecordCreate")
+  if len(spans) != 1 {
+    t.Fatalf("exported %d OnRecordCreate spans, want 1", len(spans))
+  }
+  if spans[0].Attributes["module"] != "notes" {
+    t.Errorf("module of the hook span = %q, want %q", spans[0].Attributes["module"], "notes")
+  }
+
+  queries := 0
+  for _, child := range exporter.childrenOf(spans[0]) {
+    if child.Name == "db.query" {
+      queries++
+    }
+  }
+  if queries == 0 {
+    t.Errorf("the insert of the record is not a child of the hook span")
+  }
+}
+
+func TestDBQuerySpans(t *testing.T) {
+  tests := []struct {
+    name       string
+    opts       []RegistryOption
+    statements bool
+  }{
+    {name: "default"},
+    {name: "with statements", opts: []RegistryOption{WithDBStatements()}, statements: true},
+  }
+
+  for _, tt := range tests {
+    t.Run(
+      tt.name, func(t *testing.T) {
+        exporter := &memoryExporter{}
+        opts := append([]RegistryOption{WithTracing(exporter)}, tt.opts...)
+        _, app := newTestRegistryWithOptions(t, opts, &hooksModule{testModule{name: "notes"}})
+
+        collection := core.NewBaseCollection("notes")
+        collection.Fields.Add(&core.TextField{Name: "title"})
+        if err := app.Save(collection); err != nil {
+          t.Fatalf("failed to create the collection: %v", err)
+        }
+
+        record := core.NewRecord(collection)
+        record.Set("title", "s3cret title")
+        if err := app.Save(record); err != nil {
+          t.Fatalf("failed to create the record: %v", err)
+        }
+
+        var insert *Span
+        for _, child := range exporter.childrenOf(exporter.find("OnRecordCreate")[0]) {
+          if child.Name == "db.query" && child.Attributes["db.operation"] == "INSERT" {
+            insert = &child
+          }
+        }
+        if insert == nil {
+          t.Fatalf("the insert of the record is not traced")
+        }
+
+        statement, ok := insert.Attributes["db.statement"]
+        if ok != tt.statements {
+          t.Fatalf("db.statement recorded = %v, want %v", ok, tt.statements)
+        }
+        if tt.statements && !strings.Contains(statement, "s3cret title") {
+          t.Errorf("db.statement = %q, want the SQL of the insert", statement)
+        }
+      },
+    )
+  }
+}
+
+func TestRequestSpans(t *testing.T) {
+  exporter := &memoryExporter{}
+  registry, app := newTestRegistryWithOptions(
+    t, []RegistryOption{WithTracing(exporter)}, &testModule{
+      name:   "billing",
+      prefix: "/billing",
+      routes: func(groups RouterGroups) error {
+        groups.Public.GET(
+          "/invoices/{id}", func(e *core.RequestEvent) error {
+            return e.NoContent(http.StatusNoContent)
+          },
+        )
+        return nil
+      },
+    },
+  )
+
+  handler := serveTestRegistry(t, registry, app)
+
+  tests := []struct {
+    name        string
+    path        string
+    traceparent string
+    span        string
+    module      string
+  }{
+    {
+      name:   "module route",
+      path:   "/api/billing/invoices/1",
+      span:   "GET /api/billing/invoices/{id}",
+      module: "billing",
+    },
+    {
+      name: "pocketbase route",
+      path: "/api/health",
+      span: "GET /api/health",
+    },
+    {
+      name:        "continued trace",
+      path:        "/api/health",
+      traceparent: "00-0123456789abcdef0123456789abcdef-0123456789abcdef-01",
+      span:        "GET /api/health",
+    },
+  }
+
+  for _, tt := range tests {
+    t.Run(
+      tt.name, func(t *testing.T) {
+        exporter.spans = nil
+
+        request := httptest.NewRequest(http.MethodGet, tt.path, nil)
+        if tt.traceparent != "" {
+          request.Header.Set(TraceparentHeader, tt.traceparent)
+        }
+        recorder := httptest.NewRecorder()
+        handler.ServeHTTP(recorder, request)
+
+        spans := exporter.find(tt.span)
+        if len(spans) != 1 {
+          t.Fatalf("exported %d spans named %q, want 1", len(spans), tt.span)
+        }
+        span := spans[0]
+
+        if span.Attributes["module"] != tt.module {
+          t.Errorf("module of the request span = %q, want %q", span.Attributes["module"], tt.module)
+        }
+        if header := recorder.Header().Get(TraceparentHeader); header != span.Traceparent() {
+          t.Errorf("traceparent header = %q, want %q", header, span.Traceparent())
+        }
+        if tt.traceparent != "" && (span.TraceId != tt.traceparent[3:35] || span.ParentId != tt.traceparent[36:52]) {
+          t.Errorf("request span %s/%s does not continue the trace of %q", span.TraceId, span.ParentId, tt.traceparent)
+        }
+      },
+    )
+  }
+}